@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+
+	"github.com/abhimanyu003/sttr/processors"
+	"github.com/abhimanyu003/sttr/utils"
+)
+
+// outputNameData holds the fields available in --output-name templates
+type outputNameData struct {
+	// Dir is the directory of the input file, made relative
+	Dir string
+	// Base is the file name of the input file
+	Base string
+	// Name is the file name without extension
+	Name string
+	// Ext is the extension of the input file, with the leading dot
+	Ext string
+	// Processor is the name of the processor
+	Processor string
+	// Index is the position of the file in the inputs, starting at 1
+	Index int
+}
+
+// batchFiles returns the files to process in batch mode, ok is false when
+// args is a single string or file handled by the regular input path
+func batchFiles(args []string) (files []string, ok bool, err error) {
+	if len(args) == 0 {
+		if outputDir != "" {
+			return nil, false, usageError(fmt.Errorf("--output-dir needs input files"))
+		}
+		if inPlace {
+			return nil, false, usageError(fmt.Errorf("--in-place needs input files"))
+		}
+		return nil, false, nil
+	}
+
+	if len(args) == 1 && outputDir == "" && !inPlace && !fileMode {
+		if isFile(args[0]) || args[0] == "-" || !utils.HasGlobMeta(args[0]) {
+			return nil, false, nil
+		}
+		// a pattern matching nothing is taken as a string
+		files, err := utils.Glob(args[0])
+		if err != nil || len(files) == 0 {
+			return nil, false, nil
+		}
+		return files, true, nil
+	}
+	if len(args) == 1 && fileMode && !inPlace && outputDir == "" && (isFile(args[0]) || args[0] == "-") {
+		return nil, false, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		if arg == "-" {
+			return nil, false, usageError(fmt.Errorf("- (stdin) can't be combined with other inputs, --in-place or --output-dir"))
+		}
+		matches := []string{arg}
+		if !isFile(arg) {
+			if !utils.HasGlobMeta(arg) {
+				return nil, false, inputError(fmt.Errorf("%s: no such file", arg))
+			}
+			if matches, err = utils.Glob(arg); err != nil {
+				return nil, false, usageError(fmt.Errorf("%s: %w", arg, err))
+			}
+			if len(matches) == 0 {
+				return nil, false, inputError(fmt.Errorf("no files match %q", arg))
+			}
+		}
+		for _, m := range matches {
+			// a.txt, ./a.txt and its absolute path are one file
+			key, err := filepath.Abs(m)
+			if err != nil {
+				key = filepath.Clean(m)
+			}
+			if !seen[key] {
+				seen[key] = true
+				files = append(files, m)
+			}
+		}
+	}
+
+	return files, true, nil
+}
+
+// runBatch runs p on every file with a bounded pool of workers. Results are
+// printed in the order of files: digests in a sha256sum style layout and
+// other outputs under a header naming the file, or written to --output-dir
+// or back to the files with --in-place.
+func runBatch(p processors.Processor, flags []processors.Flag, files []string) error {
+	var targets []string
+	if outputDir != "" {
+		var err error
+		if targets, err = outputTargets(p, files); err != nil {
+			return usageError(err)
+		}
+	}
+
+	type result struct {
+		out []byte
+		n   int64
+		err error
+	}
+	results := make([]chan result, len(files))
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+
+	workers := jobs
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, len(files))
+
+	work := make(chan int)
+	go func() {
+		for i := range files {
+			work <- i
+		}
+		close(work)
+	}()
+	for range workers {
+		go func() {
+			for i := range work {
+				var r result
+				switch {
+				case inPlace:
+					r.n, r.err = rewriteInPlace(p, flags, files[i])
+				case targets != nil:
+					r.n, r.err = transformToFile(p, flags, files[i], targets[i])
+				default:
+					var buf bytes.Buffer
+					r.n, r.err = transformFile(p, flags, files[i], &buf)
+					r.out = buf.Bytes()
+				}
+				results[i] <- r
+			}
+		}()
+	}
+
+	failed := 0
+	// the failure of a single file is returned, so it is printed only once
+	var fileErr error
+	print := func(w io.Writer) error {
+		printed := 0
+		for i, file := range files {
+			r := <-results[i]
+			if r.err != nil {
+				failed++
+			}
+			if jsonOutput() {
+				rep := newReport(p, flags)
+				rep.File, rep.InputBytes = file, r.n
+				switch {
+				case inPlace:
+					rep.Output = file
+				case targets != nil:
+					rep.Output = targets[i]
+				default:
+					rep.setResult(r.out)
+				}
+				rep.setError(r.err)
+				if err := writeReport(w, rep); err != nil {
+					return err
+				}
+				continue
+			}
+			if r.err != nil && len(files) == 1 {
+				fileErr = fmt.Errorf("%s: %w", file, r.err)
+				continue
+			}
+			if r.err != nil {
+				fmt.Fprintf(os.Stderr, "sttr: %s: %v\n", file, r.err)
+				continue
+			}
+			if hexdump {
+				r.out = []byte(hex.Dump(r.out))
+			}
+			if targets != nil || inPlace {
+				continue
+			}
+
+			var err error
+			if processors.IsDigest(p) {
+				_, err = fmt.Fprintf(w, "%s  %s\n", bytes.TrimSpace(r.out), file)
+			} else {
+				if printed > 0 {
+					fmt.Fprintln(w)
+				}
+				_, err = fmt.Fprintf(w, "==> %s <==\n%s", file, r.out)
+				if err == nil && !bytes.HasSuffix(r.out, []byte("\n")) {
+					_, err = fmt.Fprintln(w)
+				}
+			}
+			if err != nil {
+				return err
+			}
+			printed++
+		}
+		return nil
+	}
+
+	// with --output-format json every file gets its own report
+	current.emitted = jsonOutput()
+	var err error
+	if (targets != nil || inPlace) && !jsonOutput() {
+		err = print(io.Discard)
+	} else {
+		err = writeTo(print)
+	}
+	if err != nil {
+		return err
+	}
+	if fileErr != nil {
+		return fileErr
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, len(files))
+	}
+	return nil
+}
+
+// outputTargets returns the path every file is written to in --output-dir,
+// named with the --output-name template
+func outputTargets(p processors.Processor, files []string) ([]string, error) {
+	tmpl, err := template.New("output-name").Option("missingkey=error").Parse(outputName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --output-name: %w", err)
+	}
+
+	targets := make([]string, 0, len(files))
+	written := make(map[string]string, len(files))
+	for i, file := range files {
+		base := filepath.Base(file)
+		ext := filepath.Ext(base)
+		data := outputNameData{
+			Dir:       relativeDir(filepath.Dir(file)),
+			Base:      base,
+			Name:      strings.TrimSuffix(base, ext),
+			Ext:       ext,
+			Processor: p.Name(),
+			Index:     i + 1,
+		}
+
+		var name strings.Builder
+		if err := tmpl.Execute(&name, data); err != nil {
+			return nil, fmt.Errorf("invalid --output-name: %w", err)
+		}
+		target := filepath.Join(outputDir, name.String())
+
+		if other, ok := written[target]; ok {
+			return nil, fmt.Errorf("%s and %s would both be written to %s, use --output-name to tell them apart", other, file, target)
+		}
+		if sameFile(file, target) {
+			return nil, fmt.Errorf("%s would be overwritten by its own output", file)
+		}
+		written[target] = file
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+// transformToFile writes the result of p on the file path to target
+func transformToFile(p processors.Processor, flags []processors.Flag, path, target string) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return 0, err
+	}
+	f, err := os.Create(target)
+	if err != nil {
+		return 0, err
+	}
+	n, err := transformFile(p, flags, path, f)
+	if err != nil {
+		f.Close()
+		return n, err
+	}
+	return n, f.Close()
+}
+
+// relativeDir turns dir into a relative path without ".." elements so
+// outputs can't escape --output-dir
+func relativeDir(dir string) string {
+	var parts []string
+	for _, part := range strings.Split(filepath.ToSlash(dir), "/") {
+		if part != "" && part != "." && part != ".." && !strings.HasSuffix(part, ":") {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "."
+	}
+	return filepath.Join(parts...)
+}
+
+// sameFile reports whether a and b name the same existing file
+func sameFile(a, b string) bool {
+	fa, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	fb, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(fa, fb)
+}
+
+func isFile(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && !fi.IsDir()
+}
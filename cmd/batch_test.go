@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.txt":       "hello",
+		"b.txt":       "world\n",
+		"sub/c.txt":   "deep",
+		"sub/d.json":  "{}",
+		"other/a.txt": "again",
+	})
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantStdout string
+	}{
+		{
+			name:       "Digests",
+			args:       []string{"md5", "a.txt", "b.txt"},
+			wantStdout: "5d41402abc4b2a76b9719d911017c592  a.txt\n591785b794601e212b260e25925636fd  b.txt\n",
+		},
+		{
+			name:       "Headers",
+			args:       []string{"upper", "a.txt", "b.txt"},
+			wantStdout: "==> a.txt <==\nHELLO\n\n==> b.txt <==\nWORLD\n",
+		},
+		{
+			name:       "Double star",
+			args:       []string{"upper", "**/*.txt"},
+			wantStdout: "==> a.txt <==\nHELLO\n\n==> b.txt <==\nWORLD\n\n==> other/a.txt <==\nAGAIN\n\n==> sub/c.txt <==\nDEEP\n",
+		},
+		{
+			name:       "Duplicates are processed once",
+			args:       []string{"upper", "a.txt", "*.txt"},
+			wantStdout: "==> a.txt <==\nHELLO\n\n==> b.txt <==\nWORLD\n",
+		},
+		{
+			name:       "Spellings of one file are processed once",
+			args:       []string{"upper", "a.txt", "./a.txt", filepath.Join(dir, "a.txt"), "sub/../b.txt"},
+			wantStdout: "==> a.txt <==\nHELLO\n\n==> sub/../b.txt <==\nWORLD\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := run(t, dir, "", tt.args...)
+			if r.code != 0 {
+				t.Fatalf("exit code %d: %s", r.code, r.stderr)
+			}
+			if r.stdout != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", r.stdout, tt.wantStdout)
+			}
+		})
+	}
+}
+
+func TestBatch_Order(t *testing.T) {
+	dir := t.TempDir()
+	files := make(map[string]string)
+	var args []string
+	var want strings.Builder
+	for i := range 50 {
+		name := fmt.Sprintf("f%02d.txt", i)
+		// the first files are the largest so they finish last
+		content := strings.Repeat("x", (50-i)*10000)
+		files[name] = content
+		args = append(args, name)
+		fmt.Fprintf(&want, "%x  %s\n", sha256.Sum256([]byte(content)), name)
+	}
+	writeFiles(t, dir, files)
+
+	r := run(t, dir, "", append([]string{"sha256", "--jobs", "8"}, args...)...)
+	if r.code != 0 {
+		t.Fatalf("exit code %d: %s", r.code, r.stderr)
+	}
+	if r.stdout != want.String() {
+		t.Errorf("stdout = %q, want the files in the order given %q", r.stdout, want.String())
+	}
+}
+
+func TestBatch_OutputDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.txt":       "hello",
+		"other/a.txt": "again",
+	})
+
+	r := run(t, dir, "", "upper", "--output-dir", "out", "a.txt", "other/a.txt")
+	if r.code != 0 {
+		t.Fatalf("exit code %d: %s", r.code, r.stderr)
+	}
+	for name, want := range map[string]string{"out/a.txt": "HELLO", "out/other/a.txt": "AGAIN"} {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v, want %q", name, got, err, want)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantStderr string
+	}{
+		{
+			name:       "Collision",
+			args:       []string{"upper", "--output-dir", "out", "--output-name", "{{.Base}}", "a.txt", "other/a.txt"},
+			wantStderr: "a.txt and other/a.txt would both be written to out/a.txt, use --output-name to tell them apart",
+		},
+		{
+			name:       "Self overwrite",
+			args:       []string{"upper", "--output-dir", ".", "a.txt"},
+			wantStderr: "a.txt would be overwritten by its own output",
+		},
+		{
+			name:       "Bad template",
+			args:       []string{"upper", "--output-dir", "out", "--output-name", "{{.Nope}}", "a.txt"},
+			wantStderr: "invalid --output-name",
+		},
+		{
+			name:       "No input files",
+			args:       []string{"upper", "--output-dir", "out"},
+			wantStderr: "--output-dir needs input files",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := run(t, dir, "", tt.args...)
+			if r.code != exitUsage {
+				t.Errorf("exit code = %d, want %d", r.code, exitUsage)
+			}
+			if !strings.Contains(r.stderr, tt.wantStderr) {
+				t.Errorf("stderr = %q, want %q", r.stderr, tt.wantStderr)
+			}
+		})
+	}
+
+	if got, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(got) != "hello" {
+		t.Errorf("a.txt = %q after the failed runs, want it untouched", got)
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"unicode/utf8"
+
+	"github.com/abhimanyu003/sttr/processors"
+)
+
+// errBinaryOutput is returned instead of writing binary output to a terminal
+var errBinaryOutput = errors.New("the output is binary, use --output FILE, --hexdump or --force to print it to the terminal")
+
+// textGuard writes to w as long as the output is text: valid UTF-8 without
+// NUL bytes. A character split between two writes is held until the next one.
+type textGuard struct {
+	w       io.Writer
+	partial []byte
+}
+
+func (g *textGuard) Write(b []byte) (int, error) {
+	data := append(g.partial, b...)
+
+	// hold back the start of an incomplete character
+	cut := len(data)
+	for i := len(data) - 1; i >= 0 && i > len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	if !processors.IsText(data[:cut]) {
+		return 0, errBinaryOutput
+	}
+	g.partial = bytes.Clone(data[cut:])
+
+	if _, err := g.w.Write(data[:cut]); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Close reports output ending in the middle of a character
+func (g *textGuard) Close() error {
+	if len(g.partial) > 0 {
+		return errBinaryOutput
+	}
+	return nil
+}
+
+// hexdumpTo returns write with its output turned into a hexdump -C style dump
+func hexdumpTo(write func(w io.Writer) error) func(w io.Writer) error {
+	return func(w io.Writer) error {
+		d := hex.Dumper(w)
+		if err := write(d); err != nil {
+			d.Close()
+			return err
+		}
+		return d.Close()
+	}
+}
@@ -11,8 +11,9 @@ func init() {
 }
 
 var completionCmd = &cobra.Command{
-	Use:   "completion [bash|zsh|fish|powershell]",
-	Short: "Generate completion script",
+	Use:     "completion [bash|zsh|fish|powershell]",
+	Short:   "Generate completion script",
+	GroupID: groupCommands,
 	Long: `To load completions:
 
 Bash:
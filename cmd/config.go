@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/abhimanyu003/sttr/config"
+	"github.com/abhimanyu003/sttr/processors"
+	"github.com/spf13/cobra"
+)
+
+// userConfig is the user configuration read before the commands run,
+// userConfigErr is returned by the commands using it when the file is invalid
+var (
+	userConfig    *config.Config
+	userConfigErr error
+
+	// aliasCmds are the commands added for the user aliases
+	aliasCmds []*cobra.Command
+
+	configAll  bool
+	configPath bool
+)
+
+func init() {
+	configCmd.Flags().BoolVar(&configAll, "all", false, "Show every processor flag, not only the configured ones")
+	configCmd.Flags().BoolVar(&configPath, "path", false, "Print the path of the configuration file and exit")
+	rootCmd.AddCommand(configCmd)
+}
+
+var configCmd = &cobra.Command{
+	Use:     "config",
+	Short:   "Show the effective configuration and where each setting comes from",
+	GroupID: groupCommands,
+	Long: `Show the settings read from the configuration file merged with the
+built-in defaults.
+
+The file is $STTR_CONFIG, or sttr/config.yaml in $XDG_CONFIG_HOME or the
+user configuration directory. It sets default flag values per processor and
+aliases expanding to processor chains:
+
+  defaults:
+    json:
+      indent: true
+    bcrypt.number-of-rounds: 12
+  aliases:
+    jwtbody: "base64url-decode,json -i"
+
+Flags given on the command line always win over the configured defaults.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.Path()
+		if err != nil {
+			return err
+		}
+		if configPath {
+			fmt.Fprintln(cmd.OutOrStdout(), path)
+			return nil
+		}
+		if userConfigErr != nil {
+			return usageError(userConfigErr)
+		}
+		return printConfig(cmd.OutOrStdout(), path)
+	},
+}
+
+// loadConfig reads the user configuration and adds a command for every alias
+func loadConfig() {
+	userConfig, userConfigErr = config.Load()
+	if userConfigErr != nil {
+		return
+	}
+
+	for _, name := range userConfig.Aliases() {
+		if commandExists(name) {
+			continue
+		}
+		spec, _ := userConfig.AliasSpec(name)
+		if len(aliasCmds) == 0 {
+			rootCmd.AddGroup(&cobra.Group{ID: groupAliases, Title: "Aliases:"})
+		}
+		aliasCmd := &cobra.Command{
+			Use:     name + " [string | file]",
+			Short:   "Alias for " + spec,
+			GroupID: groupAliases,
+			Args:    cobra.RangeArgs(0, 1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				chain, err := userConfig.Alias(name)
+				if err != nil {
+					return usageError(err)
+				}
+				return runChain(name, chain, args)
+			},
+		}
+		aliasCmds = append(aliasCmds, aliasCmd)
+		rootCmd.AddCommand(aliasCmd)
+	}
+}
+
+// commandExists reports whether name is a command name or alias,
+// aliases never shadow commands
+func commandExists(name string) bool {
+	if name == "help" || name == "completion" {
+		return true
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || slices.Contains(c.Aliases, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// configFlags gives the flags not set on the command line their configured default
+func configFlags(cmd *cobra.Command, p processors.Processor, flags []processors.Flag) ([]processors.Flag, error) {
+	if userConfigErr != nil {
+		return nil, usageError(userConfigErr)
+	}
+	for _, def := range userConfig.Defaults(p.Name()) {
+		if cmd.Flags().Changed(def.Name) {
+			continue
+		}
+		for i := range flags {
+			if flags[i].Name == def.Name {
+				flags[i].Value = def.Value
+			}
+		}
+	}
+	return flags, nil
+}
+
+// printConfig writes the effective settings, with --all every processor
+// flag is listed along with the configured ones
+func printConfig(w io.Writer, path string) error {
+	source := "config file"
+	if userConfig.Path == "" {
+		fmt.Fprintf(w, "Config file: %s (not found)\n", path)
+	} else {
+		fmt.Fprintf(w, "Config file: %s\n", userConfig.Path)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "\nDefaults:")
+	n := 0
+	for _, p := range processors.All() {
+		configured := userConfig.Defaults(p.Name())
+		for _, def := range p.Flags() {
+			builtin := fmt.Sprint(def.Value)
+			if def.Value == nil {
+				builtin = ""
+			}
+			i := slices.IndexFunc(configured, func(f processors.Flag) bool { return f.Name == def.Name })
+			switch {
+			case i >= 0:
+				fmt.Fprintf(tw, "  %s.%s\t%q\t%s, default %q\n", p.Name(), def.Name, fmt.Sprint(configured[i].Value), source, builtin)
+			case configAll:
+				fmt.Fprintf(tw, "  %s.%s\t%q\tdefault\n", p.Name(), def.Name, builtin)
+			default:
+				continue
+			}
+			n++
+		}
+	}
+	if n == 0 {
+		fmt.Fprintln(tw, "  none")
+	}
+
+	fmt.Fprintln(tw, "\nAliases:")
+	for _, name := range userConfig.Aliases() {
+		spec, _ := userConfig.AliasSpec(name)
+		note := source
+		if !slices.ContainsFunc(aliasCmds, func(c *cobra.Command) bool { return c.Name() == name }) {
+			note += ", shadowed by a command"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", name, spec, note)
+	}
+	if len(userConfig.Aliases()) == 0 {
+		fmt.Fprintln(tw, "  none")
+	}
+
+	return tw.Flush()
+}
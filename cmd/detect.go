@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/abhimanyu003/sttr/processors"
+	"github.com/spf13/cobra"
+)
+
+// previewWidth is the number of characters of the decoded input shown by detect
+const previewWidth = 60
+
+var detectJSON bool
+
+func init() {
+	detectCmd.Flags().BoolVar(&detectJSON, "json", false, "Print the candidates as a JSON array, with the whole decoded output")
+	rootCmd.AddCommand(detectCmd)
+}
+
+var detectCmd = &cobra.Command{
+	Use:     "detect [string | file]",
+	Short:   "Guess the format of the input and list the decoders which succeed on it",
+	GroupID: groupCommands,
+	Long: `Guess whether the input is base64, base64url, hex, a JWT, URL encoded,
+JSON, YAML or msgpack, and list the decoders which succeed on it, the most
+likely first, with a preview of the decoded result.
+
+  sttr detect aGVsbG8gd29ybGQ=
+  pbpaste | sttr detect
+
+The command of each candidate can be run to get the whole result, a JWT
+candidate decodes the payload, the second dot-separated part of the token.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		in, err := readInput(args)
+		if err != nil {
+			return err
+		}
+		candidates := processors.Detect(in)
+
+		if detectJSON || jsonOutput() {
+			return printCandidatesJSON(cmd.OutOrStdout(), candidates)
+		}
+		if len(candidates) == 0 {
+			fmt.Fprintln(os.Stderr, "No decoder recognises the input")
+			return nil
+		}
+		return printCandidates(cmd.OutOrStdout(), candidates)
+	},
+}
+
+// detectEntry describes a candidate in the output of detect --json
+type detectEntry struct {
+	Format     string       `json:"format"`
+	Confidence float64      `json:"confidence"`
+	Command    string       `json:"command"`
+	Chain      []reportStep `json:"chain"`
+	Part       string       `json:"part,omitempty"`
+	Output     string       `json:"output"`
+	Encoding   string       `json:"encoding"`
+}
+
+// candidateCommand returns the command line running c
+func candidateCommand(c processors.Candidate) string {
+	if c.Part == "payload" {
+		return "cut -d. -f2 | " + c.Chain.CommandLine()
+	}
+	return c.Chain.CommandLine()
+}
+
+// printCandidatesJSON writes the candidates as a JSON array
+func printCandidatesJSON(w io.Writer, candidates []processors.Candidate) error {
+	entries := make([]detectEntry, 0, len(candidates))
+	for _, c := range candidates {
+		e := detectEntry{
+			Format:     c.Format,
+			Confidence: float64(int(c.Confidence*100+0.5)) / 100,
+			Command:    candidateCommand(c),
+			Chain:      newChainReport("", c.Chain).Chain,
+			Part:       c.Part,
+		}
+		e.Output, e.Encoding = processors.EncodeOutput(c.Output)
+		entries = append(entries, e)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// printCandidates writes the candidates as a table
+func printCandidates(w io.Writer, candidates []processors.Candidate) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FORMAT\tCONFIDENCE\tCOMMAND\tPREVIEW")
+	for _, c := range candidates {
+		fmt.Fprintf(tw, "%s\t%.0f%%\t%s\t%s\n", c.Format, c.Confidence*100, candidateCommand(c), preview(c.Output, previewWidth))
+	}
+	return tw.Flush()
+}
+
+// preview returns the start of out on a single line, binary data is shown in hex
+func preview(out []byte, width int) string {
+	s := strings.Join(strings.Fields(string(out)), " ")
+	if !processors.IsText(out) || strings.IndexFunc(s, unicode.IsControl) >= 0 {
+		s = "hex: " + hex.EncodeToString(out[:min(len(out), width/2)])
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"fmt"
-	"github.com/spf13/cobra"
-	"github.com/spf13/cobra/doc"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/abhimanyu003/sttr/processors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
 )
 
 func init() {
@@ -37,6 +39,50 @@ var docsCmd = &cobra.Command{
 			return err
 		}
 		rootCmd.DisableAutoGenTag = true
-		return doc.GenMarkdownTreeCustom(rootCmd, dir, filePrepender, linkHandler)
+		// the docs describe sttr, not the aliases of the user generating them
+		rootCmd.RemoveCommand(aliasCmds...)
+		if err := doc.GenMarkdownTreeCustom(rootCmd, dir, filePrepender, linkHandler); err != nil {
+			return err
+		}
+		return genCategoryDocs(filepath.Join(dir, "categories"))
 	},
 }
+
+// genCategoryDocs writes a page per processor category to dir listing the
+// commands of the category, linked to the pages of the commands
+func genCategoryDocs(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	index := "---\ntitle: Categories\nbookCollapseSection: true\n---\n"
+	if err := os.WriteFile(filepath.Join(dir, "_index.md"), []byte(index), 0o644); err != nil {
+		return err
+	}
+
+	for i, category := range processors.Categories {
+		ps := processors.ByCategory(category)
+		if len(ps) == 0 {
+			continue
+		}
+		title := processors.CategoryTitle(category)
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "---\ntitle: %s\nweight: %d\n---\n", title, i+1)
+		fmt.Fprintf(&b, "## %s\n\n", title)
+		b.WriteString("| Command | Aliases | Description |\n")
+		b.WriteString("|---------|---------|-------------|\n")
+		for _, p := range ps {
+			aliases := make([]string, 0, len(p.Alias()))
+			for _, a := range p.Alias() {
+				aliases = append(aliases, "`"+a+"`")
+			}
+			fmt.Fprintf(&b, "| [sttr %s]({{< relref \"../sttr_%s.md\" >}}) | %s | %s |\n",
+				p.Name(), p.Name(), strings.Join(aliases, " "), processors.DescriptionOf(p))
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, category+".md"), []byte(b.String()), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
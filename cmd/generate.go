@@ -10,46 +10,36 @@ import (
 	"text/template"
 
 	"github.com/abhimanyu003/sttr/utils"
-	list2 "github.com/charmbracelet/bubbles/list"
 
 	"github.com/abhimanyu003/sttr/processors"
 )
 
 type data struct {
-	Name  string
-	Camel string
-	Desc  string
-	SName string
-	Alias []string
-	Flags []processors.Flag
+	Name     string
+	Camel    string
+	Desc     string
+	Category string
+	Alias    []string
+	Flags    []processors.Flag
 }
 
 func main() {
-	list := processors.List
-	for _, item := range list {
-		p, ok := item.(processors.Processor)
-		if !ok {
-			log.Printf("item is not a processor: %v", item)
-			continue
-		}
-		i, ok := item.(list2.DefaultItem)
-		if !ok {
-			log.Printf("item is not a list.DefaultItem: %v", item)
-			continue
-		}
-
+	for _, p := range processors.All() {
 		d := data{
-			Name:  p.Name(),
-			Alias: p.Alias(),
-			Camel: utils.ToLowerCamelCase([]byte(p.Name())),
-			SName: fmt.Sprintf("%T", p),
-			Desc:  i.Description(),
-			Flags: p.Flags(),
+			Name:     p.Name(),
+			Alias:    p.Alias(),
+			Camel:    utils.ToLowerCamelCase([]byte(p.Name())),
+			Desc:     processors.DescriptionOf(p),
+			Category: processors.CategoryOf(p),
+			Flags:    p.Flags(),
 		}
 		if d.Name == "" {
 			log.Print("processor has no name")
 			continue
 		}
+		if err := processors.ValidateFlagDefs(d.Flags); err != nil {
+			log.Fatalf("processor %s: %v", d.Name, err)
+		}
 		generate(d)
 	}
 }
@@ -64,6 +54,7 @@ func generate(d data) {
 	funcMap := template.FuncMap{
 		"Lower":     strings.ToLower,
 		"ListAlias": ListAlias,
+		"Quote":     Quote,
 	}
 
 	tmpl, err := template.New("test").Funcs(funcMap).Parse(t)
@@ -91,16 +82,23 @@ func ListAlias(l []string) string {
 	return sb.String()
 }
 
+// Quote returns l as a comma separated list of Go string literals
+func Quote(l []string) string {
+	quoted := make([]string, 0, len(l))
+	for _, s := range l {
+		quoted = append(quoted, fmt.Sprintf("%q", s))
+	}
+	return strings.Join(quoted, ", ")
+}
+
 const t = `// Code generated by github.com/abhimanyu003/sttr/cmd/generate.go. DO NOT EDIT
 
 package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/abhimanyu003/sttr/processors"
-	"github.com/abhimanyu003/sttr/utils"
 	"github.com/spf13/cobra"
 )
 {{- $camel := .Camel -}}
@@ -120,75 +118,43 @@ var (
 
 func init() {
 {{- range .Flags }}{{ if .Type.IsString }}
-	{{ $camel }}Cmd.Flags().{{ .Type }}VarP(&{{ $camel }}_flag_{{ .Short }}, "{{ .Name }}", "{{ .Short }}", "{{ .Value }}", "{{ .Desc }}")
+	{{ $camel }}Cmd.Flags().{{ .Type }}VarP(&{{ $camel }}_flag_{{ .Short }}, "{{ .Name }}", "{{ .Short }}", {{ printf "%q" .Value }}, "{{ .Desc }}")
 {{- else }}	
 	{{ $camel }}Cmd.Flags().{{ .Type }}VarP(&{{ $camel }}_flag_{{ .Short }}, "{{ .Name }}", "{{ .Short }}", {{ .Value }}, "{{ .Desc }}")
 {{- end }}	
 {{- end }}
+{{- range .Flags }}{{ if .Choices }}
+	_ = {{ $camel }}Cmd.RegisterFlagCompletionFunc("{{ .Name }}", cobra.FixedCompletions([]string{ {{- Quote .Choices -}} }, cobra.ShellCompDirectiveNoFileComp))
+{{- end }}{{ end }}
+	addProcessorFlags({{ .Camel }}Cmd)
 	rootCmd.AddCommand({{ .Camel }}Cmd)
 }
 
 var {{ .Camel }}Cmd = &cobra.Command{
-	Use:     "{{ .Name }} [string]",
+	Use:     "{{ .Name }} [string | files...]",
 	Short:   "{{ .Desc }}",
 	Aliases: []string{ {{- .Alias | ListAlias -}} },
-	Args:    cobra.MaximumNArgs(1),
+	GroupID: "{{ .Category }}",
+	Args:    cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var err error
-		var out string
+		p, ok := processors.Lookup("{{ .Name }}")
+		if !ok {
+			return fmt.Errorf("unknown processor: {{ .Name }}")
+		}
 
 		flags := make([]processors.Flag, 0)
-		p := {{ .SName }}{}
 		{{- range .Flags }}
-		flags = append(flags, processors.Flag{Short: "{{.Short}}", Value: {{ $camel }}_flag_{{ .Short }}})
+		flags = append(flags, processors.Flag{Name: "{{ .Name }}", Short: "{{ .Short }}", Value: {{ $camel }}_flag_{{ .Short }}})
 		{{- end }}
-
-		if len(args) == 0 {
-			// Handle stdin/interactive input
-			in := []byte(utils.ReadMultilineInput())
-			out, err = p.Transform(in, flags...)
-			if err != nil {
-				return err
-			}
-		} else {
-			// Check if it's a file
-			if fi, err := os.Stat(args[0]); err == nil && !fi.IsDir() {
-				// It's a file - check if we should use streaming
-				const largeFileThreshold = 10 * 1024 * 1024 // 10MB
-				
-				// Use central streaming function for all processors
-				if processors.CanStream(p) && (fi.Size() > largeFileThreshold || processors.PreferStream(p)) {
-					// Use streaming
-					file, err := os.Open(args[0])
-					if err != nil {
-						return err
-					}
-					defer file.Close()
-					
-					err = processors.TransformStream(p, file, os.Stdout, flags...)
-					return err
-				} else {
-					// Use traditional method for small files
-					d, err := os.ReadFile(args[0])
-					if err != nil {
-						return err
-					}
-					out, err = p.Transform(d, flags...)
-					if err != nil {
-						return err
-					}
-				}
-			} else {
-				// Not a file, treat as string input
-				out, err = p.Transform([]byte(args[0]), flags...)
-				if err != nil {
-					return err
-				}
-			}
+		flags, err := configFlags(cmd, p, flags)
+		if err != nil {
+			return err
+		}
+		if err := processors.ValidateFlags(p, flags...); err != nil {
+			return usageError(err)
 		}
 
-		_, err = fmt.Fprint(os.Stdout, out)
-		return err
+		return runProcessor(p, flags, args)
 	},
 }
 `
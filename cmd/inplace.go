@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/abhimanyu003/sttr/processors"
+)
+
+// rewriteInPlace replaces the content of the file path with the result of p,
+// the original is left untouched when the transformation fails.
+// With --backup-suffix the original is kept next to it.
+// It returns the number of bytes read.
+func rewriteInPlace(p processors.Processor, flags []processors.Flag, path string) (int64, error) {
+	// rewrite the target of symlinks instead of replacing the link
+	path, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return 0, inputError(err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return 0, inputError(err)
+	}
+
+	var backup func() error
+	if backupSuffix != "" {
+		backup = func() error {
+			return backupFile(path, path+backupSuffix, fi.Mode().Perm())
+		}
+	}
+
+	var n int64
+	err = replaceFile(path, fi.Mode().Perm(), func(w io.Writer) (err error) {
+		n, err = transformFile(p, flags, path, w)
+		return err
+	}, backup)
+	return n, err
+}
+
+// replaceFile writes path atomically: write fills a temporary file in the
+// same directory which is renamed over path only when write succeeded.
+// beforeRename, when not nil, runs right before the rename.
+func replaceFile(path string, perm os.FileMode, write func(w io.Writer) error, beforeRename func() error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".sttr-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if beforeRename != nil {
+		if err := beforeRename(); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// link hard links files, a variable so tests can make it fail
+var link = os.Link
+
+// backupFile makes backup a copy of path, hard linking when possible
+func backupFile(path, backup string, perm os.FileMode) error {
+	if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := link(path, backup); err == nil {
+		return nil
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(backup, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// names returns the names of the entries of dir
+func names(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestInPlace(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.txt": "hello"})
+	path := filepath.Join(dir, "a.txt")
+	if err := os.Chmod(path, 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	r := run(t, dir, "", "upper", "--in-place", "--backup-suffix", ".bak", "a.txt")
+	if r.code != 0 || r.stdout != "" {
+		t.Fatalf("exit code %d, stdout %q: %s", r.code, r.stdout, r.stderr)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "HELLO" {
+		t.Errorf("a.txt = %q, want HELLO", got)
+	}
+	if got, _ := os.ReadFile(path + ".bak"); string(got) != "hello" {
+		t.Errorf("a.txt.bak = %q, want the original", got)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o640 {
+		t.Errorf("mode of a.txt = %v, %v, want -rw-r-----", fi.Mode(), err)
+	}
+	if got, want := names(t, dir), []string{"a.txt", "a.txt.bak"}; !slices.Equal(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
+
+func TestInPlace_Failure(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"bad.json": "{bad"})
+
+	r := run(t, dir, "", "json", "--in-place", "--backup-suffix", ".bak", "bad.json")
+	if r.code != exitTransform {
+		t.Errorf("exit code = %d, want %d", r.code, exitTransform)
+	}
+	if want := "Error: bad.json: invalid character 'b' looking for beginning of object key string\n"; r.stderr != want {
+		t.Errorf("stderr = %q, want the failure once %q", r.stderr, want)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "bad.json")); string(got) != "{bad" {
+		t.Errorf("bad.json = %q, want it untouched", got)
+	}
+	if got, want := names(t, dir), []string{"bad.json"}; !slices.Equal(got, want) {
+		t.Errorf("files = %v, want %v without temporary files or backup", got, want)
+	}
+}
+
+func TestInPlace_Symlink(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"target.txt": "hello"})
+	if err := os.Symlink("target.txt", filepath.Join(dir, "link.txt")); err != nil {
+		t.Skip(err)
+	}
+
+	if r := run(t, dir, "", "upper", "--in-place", "link.txt"); r.code != 0 {
+		t.Fatalf("exit code %d: %s", r.code, r.stderr)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "target.txt")); string(got) != "HELLO" {
+		t.Errorf("target.txt = %q, want HELLO", got)
+	}
+	if fi, err := os.Lstat(filepath.Join(dir, "link.txt")); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("link.txt is no longer a symlink: %v, %v", fi.Mode(), err)
+	}
+}
+
+func TestReplaceFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+	writeFiles(t, dir, map[string]string{"a.txt": "original"})
+
+	failing := errors.New("failing")
+	tests := []struct {
+		name         string
+		write        func(w io.Writer) error
+		beforeRename func() error
+		wantErr      error
+		want         string
+	}{
+		{
+			name:    "Write fails",
+			write:   func(w io.Writer) error { io.WriteString(w, "partial"); return failing },
+			wantErr: failing,
+			want:    "original",
+		},
+		{
+			name:         "Before rename fails",
+			write:        func(w io.Writer) error { _, err := io.WriteString(w, "new"); return err },
+			beforeRename: func() error { return failing },
+			wantErr:      failing,
+			want:         "original",
+		},
+		{
+			name:  "Success",
+			write: func(w io.Writer) error { _, err := io.WriteString(w, "new"); return err },
+			want:  "new",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := replaceFile(path, 0o600, tt.write, tt.beforeRename); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("replaceFile() error = %v, want %v", err, tt.wantErr)
+			}
+			if got, _ := os.ReadFile(path); string(got) != tt.want {
+				t.Errorf("a.txt = %q, want %q", got, tt.want)
+			}
+			if got := names(t, dir); !slices.Equal(got, []string{"a.txt"}) {
+				t.Errorf("files = %v, want no temporary file left", got)
+			}
+		})
+	}
+
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, %v, want -rw-------", fi.Mode(), err)
+	}
+}
+
+func TestBackupFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		link       func(oldname, newname string) error
+		wantLinked bool
+	}{
+		{name: "Hard link", link: os.Link, wantLinked: true},
+		{name: "Copy", link: func(string, string) error { return errors.New("no hard links") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(l func(string, string) error) { link = l }(link)
+			link = tt.link
+
+			dir := t.TempDir()
+			path, backup := filepath.Join(dir, "a.txt"), filepath.Join(dir, "a.txt.bak")
+			writeFiles(t, dir, map[string]string{"a.txt": "original", "a.txt.bak": "stale backup"})
+
+			if err := backupFile(path, backup, 0o640); err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := os.ReadFile(backup); string(got) != "original" {
+				t.Errorf("backup = %q, want original", got)
+			}
+			if linked := sameFile(path, backup); linked != tt.wantLinked {
+				t.Errorf("backup is a hard link = %v, want %v", linked, tt.wantLinked)
+			}
+			// the file replacing path must not change the backup
+			if err := replaceFile(path, 0o640, func(w io.Writer) error {
+				_, err := io.WriteString(w, "new")
+				return err
+			}, nil); err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := os.ReadFile(backup); string(got) != "original" {
+				t.Errorf("backup = %q after the replace, want original", got)
+			}
+		})
+	}
+}
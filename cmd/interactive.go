@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/abhimanyu003/sttr/ui"
+	"github.com/abhimanyu003/sttr/utils"
 	"github.com/spf13/cobra"
 	"io"
 )
@@ -11,15 +12,21 @@ func init() {
 }
 
 var interactiveCmd = &cobra.Command{
-	Use:   "interactive",
-	Short: "Use sttr in interactive mode",
+	Use:     "interactive",
+	Short:   "Use sttr in interactive mode",
+	GroupID: groupCommands,
 	Long: `Launches a nice terminal UI where you
 can explore the available processors interactively`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var err error
 		in := ""
 
-		if len(args) == 0 {
+		if fromClipboard {
+			in, err = utils.ReadClipboard()
+			if err != nil {
+				return err
+			}
+		} else if len(args) == 0 {
 			all, err := io.ReadAll(cmd.InOrStdin())
 			if err != nil {
 				return err
@@ -29,7 +36,11 @@ can explore the available processors interactively`,
 			in = args[0]
 		}
 
+		if userConfigErr != nil {
+			return usageError(userConfigErr)
+		}
 		x := ui.New(in)
+		x.SetConfig(userConfig)
 		x.Render()
 		return err
 	},
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/abhimanyu003/sttr/processors"
+	"github.com/sahilm/fuzzy"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listJSON  bool
+	listFuzzy bool
+	listGroup bool
+)
+
+func init() {
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "Print the processors as a JSON array")
+	listCmd.Flags().BoolVar(&listFuzzy, "fuzzy", false, "Match the filter fuzzily against names and aliases, best matches first")
+	listCmd.Flags().BoolVar(&listGroup, "group", false, "Group the processors by category")
+	rootCmd.AddCommand(listCmd)
+}
+
+var listCmd = &cobra.Command{
+	Use:     "list [filter]",
+	Short:   "List the processors with their aliases, flags and streaming mode",
+	GroupID: groupCommands,
+	Long: `List every processor with its aliases, flags, streaming mode and description.
+
+The filter keeps the processors whose name, alias, title or tag contains
+it, ignoring case. With --fuzzy the filter is matched like in the interactive
+mode, e.g. "b64d" finds base64-decode.
+
+The streaming mode tells how large inputs are processed: native (the
+processor streams itself), chunked, lines (one line at a time) or buffered
+(the whole input is read first).`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list := processors.All()
+		if len(args) == 1 {
+			list = filterProcessors(list, args[0], listFuzzy)
+		}
+		if listGroup {
+			// a stable sort keeps the filter order within a category
+			slices.SortStableFunc(list, func(a, b processors.Processor) int {
+				return processors.CompareCategories(processors.CategoryOf(a), processors.CategoryOf(b))
+			})
+		}
+
+		if listJSON || jsonOutput() {
+			return printListJSON(cmd.OutOrStdout(), list)
+		}
+		return printList(cmd.OutOrStdout(), list, listGroup)
+	},
+}
+
+// filterProcessors keeps the processors matching filter. Substring matches
+// keep the registration order, fuzzy matches are sorted by score.
+func filterProcessors(list []processors.Processor, filter string, fuzzily bool) []processors.Processor {
+	if fuzzily {
+		names := make([]string, len(list))
+		for i, p := range list {
+			names[i] = strings.Join(append([]string{p.Name()}, p.Alias()...), " ")
+		}
+		var matched []processors.Processor
+		for _, m := range fuzzy.Find(filter, names) {
+			matched = append(matched, list[m.Index])
+		}
+		return matched
+	}
+
+	filter = strings.ToLower(filter)
+	return slices.DeleteFunc(list, func(p processors.Processor) bool {
+		terms := append([]string{p.Name(), processors.TitleOf(p)}, p.Alias()...)
+		for _, s := range append(terms, processors.TagsOf(p)...) {
+			if strings.Contains(strings.ToLower(s), filter) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// printListJSON writes the processors as a JSON array
+func printListJSON(w io.Writer, list []processors.Processor) error {
+	entries := make([]processors.Info, 0, len(list))
+	for _, p := range list {
+		entries = append(entries, processors.InfoOf(p))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// printList writes the processors as a table, with group a heading is
+// written before the processors of every category
+func printList(w io.Writer, list []processors.Processor, group bool) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	indent := ""
+	if group {
+		indent = "  "
+	} else {
+		fmt.Fprintln(tw, "NAME\tALIASES\tSTREAM\tFLAGS\tDESCRIPTION")
+	}
+
+	category := ""
+	for _, p := range list {
+		e := processors.InfoOf(p)
+		if group && e.Category != category {
+			if category != "" {
+				fmt.Fprintln(tw)
+			}
+			category = e.Category
+			fmt.Fprintf(tw, "%s:\n", processors.CategoryTitle(category))
+		}
+
+		flags := make([]string, len(e.Flags))
+		for i, f := range e.Flags {
+			flags[i] = "--" + f.Name
+		}
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\t%s\n", indent, e.Name,
+			orDash(strings.Join(e.Aliases, ", ")), e.Streaming,
+			orDash(strings.Join(flags, " ")), e.Description)
+	}
+	if len(list) == 0 {
+		fmt.Fprintln(tw, "no processors match")
+	}
+	return tw.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
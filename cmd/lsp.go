@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/abhimanyu003/sttr/lsp"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	// editors pass --stdio to servers speaking over stdin and stdout, the only transport
+	lspCmd.Flags().Bool("stdio", false, "Use stdin and stdout, the default")
+	lspCmd.Flags().MarkHidden("stdio")
+	rootCmd.AddCommand(lspCmd)
+}
+
+var lspCmd = &cobra.Command{
+	Use:     "lsp",
+	Short:   "Run a language server offering the processors as code actions",
+	GroupID: groupCommands,
+	Long: `Run a Language Server Protocol server on stdin and stdout which offers
+"sttr: <processor>" code actions on the selection of any LSP capable editor,
+e.g. "sttr: Base64 Decode", "sttr: Format JSON" or "sttr: Sort Lines".
+
+The actions replace the selection with the output of the processor, which
+only runs once an action is picked and can be cancelled. Processors with
+flags, and every processor for editors which can't resolve code actions,
+are the sttr.transform command, whose argument
+{"uri", "range", "processor", "flags"} can be sent by editor mappings with
+other flag values. Failures are shown as editor messages.
+
+The defaults of the configuration file apply to the actions too.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userConfigErr != nil {
+			return usageError(userConfigErr)
+		}
+		if err := lsp.Serve(cmd.Context(), os.Stdin, os.Stdout, lsp.Options{Config: userConfig}); err != nil {
+			return inputError(err)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/abhimanyu003/sttr/mcp"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+var mcpCmd = &cobra.Command{
+	Use:     "mcp",
+	Short:   "Serve the processors as Model Context Protocol tools over stdio",
+	GroupID: groupCommands,
+	Long: `Run a Model Context Protocol server on stdin and stdout exposing every
+processor as a tool, so agents can run exact encodings and hashes.
+
+The arguments of a tool are the input string, its encoding (utf-8 or base64)
+and the flags of the processor. Outputs which aren't text, invalid UTF-8
+or containing NUL bytes, are returned base64 encoded.
+
+  {"mcpServers": {"sttr": {"command": "sttr", "args": ["mcp"]}}}
+
+The defaults of the configuration file apply to the tools too.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userConfigErr != nil {
+			return usageError(userConfigErr)
+		}
+		if err := mcp.Serve(cmd.Context(), os.Stdin, os.Stdout, mcp.Options{Config: userConfig, Version: Version}); err != nil {
+			return inputError(err)
+		}
+		return nil
+	},
+}
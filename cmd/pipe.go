@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/abhimanyu003/sttr/processors"
+	"github.com/abhimanyu003/sttr/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(pipeCmd)
+}
+
+var pipeCmd = &cobra.Command{
+	Use:     "pipe [chain] [string]",
+	Short:   "Chain multiple processors in one invocation",
+	GroupID: groupCommands,
+	Long: `Apply multiple processors one after another, the output of each step
+is used as the input of the next one.
+
+Steps are separated by commas and each step can have its own flags:
+
+  sttr pipe "base64-decode,json --indent,json-yaml" file.txt
+  echo "aGVsbG8=" | sttr pipe "base64-decode,upper"`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userConfigErr != nil {
+			return usageError(userConfigErr)
+		}
+		chain, err := userConfig.ParseChain(args[0])
+		if err != nil {
+			return usageError(err)
+		}
+		return runChain(cmd.Name(), chain, args[1:])
+	},
+}
+
+// runChain runs chain on the input given in args and writes the result,
+// name is the command running it
+func runChain(name string, chain processors.Chain, args []string) error {
+	current.report = newChainReport(name, chain)
+
+	in, err := readInput(args)
+	if err != nil {
+		return err
+	}
+	current.InputBytes = int64(len(in))
+
+	out, err := chain.Transform(in)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput([]byte(out))
+}
+
+// readInput reads the input of commands taking a single input: the
+// clipboard, the file or string in args, or stdin
+func readInput(args []string) ([]byte, error) {
+	switch {
+	case fromClipboard:
+		if len(args) > 0 {
+			return nil, usageError(fmt.Errorf("--from-clipboard can't be used with an input argument"))
+		}
+		s, err := utils.ReadClipboard()
+		if err != nil {
+			return nil, inputError(err)
+		}
+		return []byte(s), nil
+	case len(args) == 0 || args[0] == "-":
+		s, err := utils.ReadStdin(terminator)
+		if err != nil {
+			return nil, inputError(err)
+		}
+		return []byte(s), nil
+	}
+
+	if fi, err := os.Stat(args[0]); err == nil && !fi.IsDir() {
+		in, err := os.ReadFile(args[0])
+		if err != nil {
+			return nil, inputError(err)
+		}
+		return in, nil
+	}
+	return []byte(args[0]), nil
+}
@@ -4,74 +4,37 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/abhimanyu003/sttr/processors"
-	"github.com/abhimanyu003/sttr/utils"
 	"github.com/spf13/cobra"
 )
 
 func init() {
+	addProcessorFlags(base32DecodeCmd)
 	rootCmd.AddCommand(base32DecodeCmd)
 }
 
 var base32DecodeCmd = &cobra.Command{
-	Use:     "base32-decode [string]",
+	Use:     "base32-decode [string | files...]",
 	Short:   "Decode your base32 text",
 	Aliases: []string{"b32-dec", "b32-decode"},
-	Args:    cobra.MaximumNArgs(1),
+	GroupID: "encoding",
+	Args:    cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var err error
-		var out string
+		p, ok := processors.Lookup("base32-decode")
+		if !ok {
+			return fmt.Errorf("unknown processor: base32-decode")
+		}
 
 		flags := make([]processors.Flag, 0)
-		p := processors.Base32Decode{}
-
-		if len(args) == 0 {
-			// Handle stdin/interactive input
-			in := []byte(utils.ReadMultilineInput())
-			out, err = p.Transform(in, flags...)
-			if err != nil {
-				return err
-			}
-		} else {
-			// Check if it's a file
-			if fi, err := os.Stat(args[0]); err == nil && !fi.IsDir() {
-				// It's a file - check if we should use streaming
-				const largeFileThreshold = 10 * 1024 * 1024 // 10MB
-				
-				// Use central streaming function for all processors
-				if processors.CanStream(p) && (fi.Size() > largeFileThreshold || processors.PreferStream(p)) {
-					// Use streaming
-					file, err := os.Open(args[0])
-					if err != nil {
-						return err
-					}
-					defer file.Close()
-					
-					err = processors.TransformStream(p, file, os.Stdout, flags...)
-					return err
-				} else {
-					// Use traditional method for small files
-					d, err := os.ReadFile(args[0])
-					if err != nil {
-						return err
-					}
-					out, err = p.Transform(d, flags...)
-					if err != nil {
-						return err
-					}
-				}
-			} else {
-				// Not a file, treat as string input
-				out, err = p.Transform([]byte(args[0]), flags...)
-				if err != nil {
-					return err
-				}
-			}
+		flags, err := configFlags(cmd, p, flags)
+		if err != nil {
+			return err
+		}
+		if err := processors.ValidateFlags(p, flags...); err != nil {
+			return usageError(err)
 		}
 
-		_, err = fmt.Fprint(os.Stdout, out)
-		return err
+		return runProcessor(p, flags, args)
 	},
 }
@@ -4,10 +4,8 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/abhimanyu003/sttr/processors"
-	"github.com/abhimanyu003/sttr/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -15,67 +13,32 @@ var blake2B_flag_s uint
 
 func init() {	
 	blake2BCmd.Flags().UintVarP(&blake2B_flag_s, "size", "s", 64, "Hash size in bytes (1-64)")
+	addProcessorFlags(blake2BCmd)
 	rootCmd.AddCommand(blake2BCmd)
 }
 
 var blake2BCmd = &cobra.Command{
-	Use:     "blake2b [string]",
+	Use:     "blake2b [string | files...]",
 	Short:   "Get the BLAKE2b hash of your text",
 	Aliases: []string{"blake2b-hash", "blake2b-sum"},
-	Args:    cobra.MaximumNArgs(1),
+	GroupID: "hashing",
+	Args:    cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var err error
-		var out string
+		p, ok := processors.Lookup("blake2b")
+		if !ok {
+			return fmt.Errorf("unknown processor: blake2b")
+		}
 
 		flags := make([]processors.Flag, 0)
-		p := processors.BLAKE2b{}
-		flags = append(flags, processors.Flag{Short: "s", Value: blake2B_flag_s})
-
-		if len(args) == 0 {
-			// Handle stdin/interactive input
-			in := []byte(utils.ReadMultilineInput())
-			out, err = p.Transform(in, flags...)
-			if err != nil {
-				return err
-			}
-		} else {
-			// Check if it's a file
-			if fi, err := os.Stat(args[0]); err == nil && !fi.IsDir() {
-				// It's a file - check if we should use streaming
-				const largeFileThreshold = 10 * 1024 * 1024 // 10MB
-				
-				// Use central streaming function for all processors
-				if processors.CanStream(p) && (fi.Size() > largeFileThreshold || processors.PreferStream(p)) {
-					// Use streaming
-					file, err := os.Open(args[0])
-					if err != nil {
-						return err
-					}
-					defer file.Close()
-					
-					err = processors.TransformStream(p, file, os.Stdout, flags...)
-					return err
-				} else {
-					// Use traditional method for small files
-					d, err := os.ReadFile(args[0])
-					if err != nil {
-						return err
-					}
-					out, err = p.Transform(d, flags...)
-					if err != nil {
-						return err
-					}
-				}
-			} else {
-				// Not a file, treat as string input
-				out, err = p.Transform([]byte(args[0]), flags...)
-				if err != nil {
-					return err
-				}
-			}
+		flags = append(flags, processors.Flag{Name: "size", Short: "s", Value: blake2B_flag_s})
+		flags, err := configFlags(cmd, p, flags)
+		if err != nil {
+			return err
+		}
+		if err := processors.ValidateFlags(p, flags...); err != nil {
+			return usageError(err)
 		}
 
-		_, err = fmt.Fprint(os.Stdout, out)
-		return err
+		return runProcessor(p, flags, args)
 	},
 }
@@ -4,10 +4,8 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/abhimanyu003/sttr/processors"
-	"github.com/abhimanyu003/sttr/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -15,67 +13,33 @@ var crc32_flag_p string
 
 func init() {
 	crc32Cmd.Flags().StringVarP(&crc32_flag_p, "polynomial", "p", "ieee", "CRC32 polynomial (ieee, castagnoli, koopman)")
+	_ = crc32Cmd.RegisterFlagCompletionFunc("polynomial", cobra.FixedCompletions([]string{"ieee", "castagnoli", "koopman"}, cobra.ShellCompDirectiveNoFileComp))
+	addProcessorFlags(crc32Cmd)
 	rootCmd.AddCommand(crc32Cmd)
 }
 
 var crc32Cmd = &cobra.Command{
-	Use:     "crc32 [string]",
+	Use:     "crc32 [string | files...]",
 	Short:   "Get the CRC32 checksum of your text",
 	Aliases: []string{"crc32-sum", "crc32-checksum"},
-	Args:    cobra.MaximumNArgs(1),
+	GroupID: "hashing",
+	Args:    cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var err error
-		var out string
+		p, ok := processors.Lookup("crc32")
+		if !ok {
+			return fmt.Errorf("unknown processor: crc32")
+		}
 
 		flags := make([]processors.Flag, 0)
-		p := processors.CRC32{}
-		flags = append(flags, processors.Flag{Short: "p", Value: crc32_flag_p})
-
-		if len(args) == 0 {
-			// Handle stdin/interactive input
-			in := []byte(utils.ReadMultilineInput())
-			out, err = p.Transform(in, flags...)
-			if err != nil {
-				return err
-			}
-		} else {
-			// Check if it's a file
-			if fi, err := os.Stat(args[0]); err == nil && !fi.IsDir() {
-				// It's a file - check if we should use streaming
-				const largeFileThreshold = 10 * 1024 * 1024 // 10MB
-				
-				// Use central streaming function for all processors
-				if processors.CanStream(p) && (fi.Size() > largeFileThreshold || processors.PreferStream(p)) {
-					// Use streaming
-					file, err := os.Open(args[0])
-					if err != nil {
-						return err
-					}
-					defer file.Close()
-					
-					err = processors.TransformStream(p, file, os.Stdout, flags...)
-					return err
-				} else {
-					// Use traditional method for small files
-					d, err := os.ReadFile(args[0])
-					if err != nil {
-						return err
-					}
-					out, err = p.Transform(d, flags...)
-					if err != nil {
-						return err
-					}
-				}
-			} else {
-				// Not a file, treat as string input
-				out, err = p.Transform([]byte(args[0]), flags...)
-				if err != nil {
-					return err
-				}
-			}
+		flags = append(flags, processors.Flag{Name: "polynomial", Short: "p", Value: crc32_flag_p})
+		flags, err := configFlags(cmd, p, flags)
+		if err != nil {
+			return err
+		}
+		if err := processors.ValidateFlags(p, flags...); err != nil {
+			return usageError(err)
 		}
 
-		_, err = fmt.Fprint(os.Stdout, out)
-		return err
+		return runProcessor(p, flags, args)
 	},
 }
@@ -4,10 +4,8 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/abhimanyu003/sttr/processors"
-	"github.com/abhimanyu003/sttr/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -15,67 +13,32 @@ var removeSpaces_flag_s string
 
 func init() {
 	removeSpacesCmd.Flags().StringVarP(&removeSpaces_flag_s, "separator", "s", "", "Separator to split spaces")
+	addProcessorFlags(removeSpacesCmd)
 	rootCmd.AddCommand(removeSpacesCmd)
 }
 
 var removeSpacesCmd = &cobra.Command{
-	Use:     "remove-spaces [string]",
+	Use:     "remove-spaces [string | files...]",
 	Short:   "Remove all spaces + new lines",
 	Aliases: []string{"remove-space", "trim-spaces", "trim-space"},
-	Args:    cobra.MaximumNArgs(1),
+	GroupID: "other",
+	Args:    cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var err error
-		var out string
+		p, ok := processors.Lookup("remove-spaces")
+		if !ok {
+			return fmt.Errorf("unknown processor: remove-spaces")
+		}
 
 		flags := make([]processors.Flag, 0)
-		p := processors.RemoveSpaces{}
-		flags = append(flags, processors.Flag{Short: "s", Value: removeSpaces_flag_s})
-
-		if len(args) == 0 {
-			// Handle stdin/interactive input
-			in := []byte(utils.ReadMultilineInput())
-			out, err = p.Transform(in, flags...)
-			if err != nil {
-				return err
-			}
-		} else {
-			// Check if it's a file
-			if fi, err := os.Stat(args[0]); err == nil && !fi.IsDir() {
-				// It's a file - check if we should use streaming
-				const largeFileThreshold = 10 * 1024 * 1024 // 10MB
-				
-				// Use central streaming function for all processors
-				if processors.CanStream(p) && (fi.Size() > largeFileThreshold || processors.PreferStream(p)) {
-					// Use streaming
-					file, err := os.Open(args[0])
-					if err != nil {
-						return err
-					}
-					defer file.Close()
-					
-					err = processors.TransformStream(p, file, os.Stdout, flags...)
-					return err
-				} else {
-					// Use traditional method for small files
-					d, err := os.ReadFile(args[0])
-					if err != nil {
-						return err
-					}
-					out, err = p.Transform(d, flags...)
-					if err != nil {
-						return err
-					}
-				}
-			} else {
-				// Not a file, treat as string input
-				out, err = p.Transform([]byte(args[0]), flags...)
-				if err != nil {
-					return err
-				}
-			}
+		flags = append(flags, processors.Flag{Name: "separator", Short: "s", Value: removeSpaces_flag_s})
+		flags, err := configFlags(cmd, p, flags)
+		if err != nil {
+			return err
+		}
+		if err := processors.ValidateFlags(p, flags...); err != nil {
+			return usageError(err)
 		}
 
-		_, err = fmt.Fprint(os.Stdout, out)
-		return err
+		return runProcessor(p, flags, args)
 	},
 }
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/abhimanyu003/sttr/processors"
+	"github.com/spf13/cobra"
+)
+
+// exit codes of sttr, errors which are not classified are transform errors
+const (
+	exitTransform = 1
+	exitUsage     = 2
+	exitInput     = 3
+)
+
+// errorKind tells usage errors (bad flags or arguments), input errors
+// (missing or unreadable input) and transform errors apart
+type errorKind string
+
+const (
+	kindUsage     errorKind = "usage"
+	kindInput     errorKind = "input"
+	kindTransform errorKind = "transform"
+)
+
+type kindError struct {
+	kind errorKind
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() error {
+	return e.err
+}
+
+// usageError marks err as a usage error, nil stays nil
+func usageError(err error) error {
+	return withKind(kindUsage, err)
+}
+
+// inputError marks err as an input error, nil stays nil
+func inputError(err error) error {
+	return withKind(kindInput, err)
+}
+
+func withKind(kind errorKind, err error) error {
+	var ke *kindError
+	if err == nil || errors.As(err, &ke) {
+		return err
+	}
+	return &kindError{kind: kind, err: err}
+}
+
+// kindOf returns the kind of err, transform when it was not classified
+func kindOf(err error) errorKind {
+	var ke *kindError
+	if errors.As(err, &ke) {
+		return ke.kind
+	}
+	return kindTransform
+}
+
+// exitCode returns the process exit code for err
+func exitCode(err error) int {
+	switch kindOf(err) {
+	case kindUsage:
+		return exitUsage
+	case kindInput:
+		return exitInput
+	}
+	return exitTransform
+}
+
+// report is the envelope written for --output-format json
+type report struct {
+	Processor  string         `json:"processor,omitempty"`
+	Flags      map[string]any `json:"flags,omitempty"`
+	Chain      []reportStep   `json:"chain,omitempty"`
+	File       string         `json:"file,omitempty"`
+	Output     string         `json:"output,omitempty"`
+	InputBytes int64          `json:"input_bytes"`
+	Result     string         `json:"result"`
+	Encoding   string         `json:"encoding"`
+	Error      *reportError   `json:"error"`
+}
+
+// reportStep is a step of the chain run by pipe or a user alias
+type reportStep struct {
+	Processor string         `json:"processor"`
+	Flags     map[string]any `json:"flags,omitempty"`
+}
+
+type reportError struct {
+	Kind    errorKind `json:"kind"`
+	Message string    `json:"message"`
+}
+
+// current is the report of the command being run, emitted once it returns
+// unless the command emitted its own reports
+var current struct {
+	report
+	emitted bool
+}
+
+// jsonOutput reports whether the output is the --output-format json envelope
+func jsonOutput() bool {
+	return outputFormat == "json"
+}
+
+// newReport describes a run of p with flags
+func newReport(p processors.Processor, flags []processors.Flag) report {
+	return report{Processor: p.Name(), Flags: flagMap(p, flags)}
+}
+
+// newChainReport describes a run of chain, named after the command running it
+func newChainReport(name string, chain processors.Chain) report {
+	r := report{Processor: name}
+	for _, step := range chain {
+		r.Chain = append(r.Chain, reportStep{
+			Processor: step.Processor.Name(),
+			Flags:     flagMap(step.Processor, step.Flags),
+		})
+	}
+	return r
+}
+
+// reportName returns the processor field of the report of cmd: the name of
+// the processor, chain or alias it runs, "" for the other commands
+func reportName(cmd *cobra.Command) string {
+	if cmd == nil || cmd.Parent() != rootCmd {
+		return ""
+	}
+	if _, ok := processors.Lookup(cmd.Name()); ok || cmd == pipeCmd || cmd.GroupID == groupAliases {
+		return cmd.Name()
+	}
+	return ""
+}
+
+// flagMap returns the value of every flag of p, nil when p has no flags
+func flagMap(p processors.Processor, flags []processors.Flag) map[string]any {
+	values, err := processors.ParseFlags(p.Flags(), flags...)
+	if err != nil || len(p.Flags()) == 0 {
+		return nil
+	}
+	return values.Map()
+}
+
+// setResult records out in r, results which are not text are base64 encoded
+func (r *report) setResult(out []byte) {
+	r.Result, r.Encoding = processors.EncodeOutput(out)
+}
+
+// setError records err in r, the result of a failed run is empty
+func (r *report) setError(err error) {
+	if err == nil {
+		return
+	}
+	r.Result, r.Encoding = "", ""
+	r.Error = &reportError{Kind: kindOf(err), Message: err.Error()}
+}
+
+// writeReport writes r as a single line of JSON
+func writeReport(w io.Writer, r report) error {
+	if r.Encoding == "" && r.Error == nil {
+		r.Encoding = "utf-8"
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(r)
+}
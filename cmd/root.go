@@ -2,11 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/abhimanyu003/sttr/processors"
 	"github.com/abhimanyu003/sttr/ui"
+	"github.com/abhimanyu003/sttr/utils"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var rootCmd = &cobra.Command{
@@ -16,21 +20,122 @@ var rootCmd = &cobra.Command{
 transformation operations on the input text.
 
 Complete documentation is available at https://github.com/abhimanyu003/sttr`,
+	// errors are printed by Execute, usage only for usage errors
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		commandStarted = true
+		switch {
+		case outputFormat != "text" && outputFormat != "json":
+			return usageError(fmt.Errorf(`invalid --output-format %q, must be one of: text, json`, outputFormat))
+		case jsonOutput() && toClipboard:
+			return usageError(fmt.Errorf("--output-format json can't be used with --to-clipboard"))
+		}
+		return nil
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
-			x := ui.New("")
+			in := ""
+			if fromClipboard {
+				var err error
+				if in, err = utils.ReadClipboard(); err != nil {
+					return err
+				}
+			}
+			if userConfigErr != nil {
+				return usageError(userConfigErr)
+			}
+			x := ui.New(in)
+			x.SetConfig(userConfig)
 			x.Render()
 		}
 		return nil
 	},
 }
 
+var (
+	fromClipboard bool
+	toClipboard   bool
+	terminator    string
+	outputFormat  string
+	hexdump       bool
+	force         bool
+
+	// commandStarted is set once the arguments of the command were parsed,
+	// errors returned before are usage errors
+	commandStarted bool
+)
+
+// groups of the help besides the processor categories
+const (
+	groupCommands = "commands"
+	groupAliases  = "aliases"
+)
+
 func init() {
+	// processor commands are listed in the help by category
+	rootCmd.AddGroup(&cobra.Group{ID: groupCommands, Title: "Commands:"})
+	for _, c := range processors.Categories {
+		rootCmd.AddGroup(&cobra.Group{ID: c, Title: processors.CategoryTitle(c) + ":"})
+	}
+	rootCmd.SetHelpCommandGroupID(groupCommands)
+	rootCmd.SetCompletionCommandGroupID(groupCommands)
+
+	rootCmd.PersistentFlags().BoolVar(&fromClipboard, "from-clipboard", false, "Read the input from the clipboard")
+	rootCmd.PersistentFlags().BoolVar(&toClipboard, "to-clipboard", false, "Copy the output to the clipboard instead of printing it")
+	rootCmd.PersistentFlags().StringVar(&terminator, "terminator", "", "Line ending the input typed in a terminal (default: two empty lines)")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output-format", "text", "Output format: text, or json for a report with the result, the flags used and errors")
+	rootCmd.PersistentFlags().BoolVar(&hexdump, "hexdump", false, "Print the output as a hex dump")
+	rootCmd.PersistentFlags().BoolVar(&force, "force", false, "Print binary output to the terminal anyway")
+	_ = rootCmd.RegisterFlagCompletionFunc("output-format", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError(err)
+	})
 }
 
+// Execute runs sttr and exits with exitUsage, exitInput or exitTransform
+// when the command fails
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	loadConfig()
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil && !commandStarted {
+		err = usageError(err)
+		// unknown commands and bad flags stop the parsing before --output-format
+		scanOutputFormat(os.Args[1:])
 	}
+	if err != nil && current.Processor == "" {
+		current.Processor = reportName(cmd)
+	}
+
+	if jsonOutput() && !current.emitted && (current.Processor != "" || err != nil) {
+		current.setError(err)
+		werr := writeTo(func(w io.Writer) error {
+			return writeReport(w, current.report)
+		})
+		if werr != nil {
+			fmt.Fprintln(os.Stderr, "Error:", werr)
+		}
+		if err == nil {
+			err = werr
+		}
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		if kindOf(err) == kindUsage {
+			fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+		}
+	}
+
+	if err != nil {
+		os.Exit(exitCode(err))
+	}
+}
+
+// scanOutputFormat sets --output-format from args, skipping every other
+// flag, for the errors returned before the command line was parsed
+func scanOutputFormat(args []string) {
+	fs := pflag.NewFlagSet("sttr", pflag.ContinueOnError)
+	fs.ParseErrorsWhitelist.UnknownFlags = true
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&outputFormat, "output-format", outputFormat, "")
+	_ = fs.Parse(args)
 }
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExecute_Report(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"bad.json": "{bad"})
+
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		want     string
+		wantCode int
+	}{
+		{
+			name: "Result",
+			args: []string{"upper", "hello"},
+			want: `{"processor":"upper","input_bytes":5,"result":"HELLO","encoding":"utf-8","error":null}`,
+		},
+		{
+			name:  "Stdin",
+			args:  []string{"upper"},
+			stdin: "hi\n",
+			want:  `{"processor":"upper","input_bytes":3,"result":"HI","encoding":"utf-8","error":null}`,
+		},
+		{
+			name: "Binary result",
+			args: []string{"hex-decode", "00ff"},
+			want: `{"processor":"hex-decode","input_bytes":4,"result":"AP8=","encoding":"base64","error":null}`,
+		},
+		{
+			name: "Result with NUL bytes",
+			args: []string{"base64-decode", "AAE="},
+			want: `{"processor":"base64-decode","flags":{"raw":false},"input_bytes":4,"result":"AAE=","encoding":"base64","error":null}`,
+		},
+		{
+			name: "Chain",
+			args: []string{"pipe", "base64-decode,upper", "aGk="},
+			want: `{"processor":"pipe","chain":[{"processor":"base64-decode","flags":{"raw":false}},{"processor":"upper"}],"input_bytes":4,"result":"HI","encoding":"utf-8","error":null}`,
+		},
+		{
+			name:     "Transform error",
+			args:     []string{"json", "bad.json"},
+			want:     `{"processor":"json","flags":{"indent":false},"input_bytes":4,"result":"","encoding":"","error":{"kind":"transform","message":"invalid character 'b' looking for beginning of object key string"}}`,
+			wantCode: exitTransform,
+		},
+		{
+			name:     "Input error",
+			args:     []string{"upper", "--file", "nope.txt"},
+			want:     `{"processor":"upper","input_bytes":0,"result":"","encoding":"","error":{"kind":"input","message":"nope.txt: no such file"}}`,
+			wantCode: exitInput,
+		},
+		{
+			name:     "Invalid flag value",
+			args:     []string{"crc32", "-p", "nope", "x"},
+			want:     `{"processor":"crc32","input_bytes":0,"result":"","encoding":"","error":{"kind":"usage","message":"flag --polynomial: invalid value \"nope\", must be one of: ieee, castagnoli, koopman"}}`,
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Unknown flag",
+			args:     []string{"upper", "--bad", "x"},
+			want:     `{"processor":"upper","input_bytes":0,"result":"","encoding":"","error":{"kind":"usage","message":"unknown flag: --bad"}}`,
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Missing chain",
+			args:     []string{"pipe"},
+			want:     `{"processor":"pipe","input_bytes":0,"result":"","encoding":"","error":{"kind":"usage","message":"accepts between 1 and 2 arg(s), received 0"}}`,
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Unknown command",
+			args:     []string{"nope"},
+			want:     `{"input_bytes":0,"result":"","encoding":"","error":{"kind":"usage","message":"unknown command \"nope\" for \"sttr\"\n\nDid you mean this?\n\tpipe\n"}}`,
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Unknown subcommand",
+			args:     []string{"config", "nope"},
+			want:     `{"input_bytes":0,"result":"","encoding":"","error":{"kind":"usage","message":"unknown command \"nope\" for \"sttr config\""}}`,
+			wantCode: exitUsage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// --output-format is found wherever it is on the command line
+			for _, args := range [][]string{
+				append([]string{"--output-format", "json"}, tt.args...),
+				append(append([]string{}, tt.args...), "--output-format=json"),
+			} {
+				r := run(t, dir, tt.stdin, args...)
+				if r.code != tt.wantCode {
+					t.Errorf("%v: exit code = %d, want %d", args, r.code, tt.wantCode)
+				}
+				if got := strings.TrimSuffix(r.stdout, "\n"); got != tt.want {
+					t.Errorf("%v: stdout = %s\nwant %s", args, got, tt.want)
+				}
+				if r.stderr != "" {
+					t.Errorf("%v: stderr = %q, want the error only in the report", args, r.stderr)
+				}
+			}
+		})
+	}
+}
+
+func TestExecute_Stdin(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		want  string
+	}{
+		{name: "Decoder", args: []string{"hex-decode"}, stdin: "68656c6c6f\n", want: "hello"},
+		{name: "Streamed", args: []string{"md5"}, stdin: "hello\n", want: "5d41402abc4b2a76b9719d911017c592"},
+		{name: "CRLF", args: []string{"base64-encode"}, stdin: "hello\r\n", want: "aGVsbG8="},
+		{name: "Single line break", args: []string{"upper"}, stdin: "a\n\n", want: "A\n"},
+		{name: "No line break", args: []string{"upper"}, stdin: "a\r", want: "A\r"},
+		{name: "Dash", args: []string{"hex-decode", "-"}, stdin: "68656c6c6f\n", want: "hello"},
+		{name: "Pipe", args: []string{"pipe", "hex-decode,upper"}, stdin: "68656c6c6f\n", want: "HELLO"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := run(t, t.TempDir(), tt.stdin, tt.args...)
+			if r.code != 0 {
+				t.Fatalf("exit code = %d, stderr = %q", r.code, r.stderr)
+			}
+			if r.stdout != tt.want {
+				t.Errorf("stdout = %q, want %q", r.stdout, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecute_ExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"bad.json": "{bad"})
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStderr string
+	}{
+		{name: "Success", args: []string{"upper", "x"}},
+		{name: "Transform error", args: []string{"json", "bad.json"}, wantCode: exitTransform, wantStderr: "Error: invalid character 'b' looking for beginning of object key string\n"},
+		{name: "Input error", args: []string{"upper", "--file", "nope.txt"}, wantCode: exitInput, wantStderr: "Error: nope.txt: no such file\n"},
+		{name: "Unknown flag", args: []string{"upper", "--bad"}, wantCode: exitUsage, wantStderr: "Error: unknown flag: --bad\nRun 'sttr upper --help' for usage.\n"},
+		{name: "Invalid flag value", args: []string{"zeropad", "-n", "x", "5"}, wantCode: exitUsage, wantStderr: "Error: invalid argument"},
+		{name: "Conflicting flags", args: []string{"upper", "--file", "--string", "x"}, wantCode: exitUsage, wantStderr: "Error: --file and --string can't be used together\n"},
+		{name: "Unknown command", args: []string{"nope"}, wantCode: exitUsage, wantStderr: "Error: unknown command \"nope\" for \"sttr\""},
+		{name: "Invalid output format", args: []string{"--output-format", "yaml", "upper", "x"}, wantCode: exitUsage, wantStderr: "Error: invalid --output-format \"yaml\", must be one of: text, json\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := run(t, dir, "", tt.args...)
+			if r.code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", r.code, tt.wantCode)
+			}
+			if !strings.HasPrefix(r.stderr, tt.wantStderr) || (tt.wantStderr == "" && r.stderr != "") {
+				t.Errorf("stderr = %q, want %q", r.stderr, tt.wantStderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/abhimanyu003/sttr/rpc"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(rpcCmd)
+}
+
+var rpcCmd = &cobra.Command{
+	Use:     "rpc",
+	Short:   "Serve the processors over JSON-RPC on stdin and stdout",
+	GroupID: groupCommands,
+	Long: `Answer newline-delimited JSON-RPC 2.0 requests read from stdin on stdout,
+for editors running many transformations without starting sttr each time.
+
+Methods:
+
+  list                      every processor with its flags
+  describe   {processor}    a single processor
+  transform  {processor, flags, input, encoding, chain}
+
+  {"jsonrpc":"2.0","id":1,"method":"transform","params":{"processor":"zeropad","flags":{"n":2},"input":"7"}}
+  {"jsonrpc":"2.0","id":1,"result":{"result":"007","encoding":"utf-8","command":"sttr zeropad -n 2"}}
+
+chain is run on the output of processor, in the syntax of sttr pipe, and
+either can be omitted. Requests run concurrently, the $/cancelRequest
+notification {"id": <request id>} cancels a pending one.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userConfigErr != nil {
+			return usageError(userConfigErr)
+		}
+		if err := rpc.Serve(cmd.Context(), os.Stdin, os.Stdout, rpc.Options{Config: userConfig}); err != nil {
+			return inputError(err)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/abhimanyu003/sttr/processors"
+	"github.com/abhimanyu003/sttr/utils"
+	"github.com/spf13/cobra"
+)
+
+// largeFileThreshold is the file size above which streaming is used
+// for processors which support it
+const largeFileThreshold = 10 * 1024 * 1024 // 10MB
+
+// flags shared by every processor command
+var (
+	fileMode     bool
+	stringMode   bool
+	outputPath   string
+	outputDir    string
+	outputName   string
+	jobs         int
+	inPlace      bool
+	backupSuffix string
+)
+
+// addProcessorFlags adds the input and output flags shared by every processor command,
+// shorthands already used by the processor flags are left out
+func addProcessorFlags(cmd *cobra.Command) {
+	short := func(s string) string {
+		if cmd.Flags().ShorthandLookup(s) != nil {
+			return ""
+		}
+		return s
+	}
+
+	cmd.Flags().BoolVarP(&fileMode, "file", short("f"), false, "Read the input from the files given as arguments, - for stdin")
+	cmd.Flags().BoolVar(&stringMode, "string", false, "Use the argument as the input even if a file with that name exists")
+	cmd.Flags().StringVarP(&outputPath, "output", short("o"), "", "Write the result to this file instead of stdout")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "Write the result of every input file to this directory")
+	cmd.Flags().StringVar(&outputName, "output-name", "{{.Dir}}/{{.Base}}", "File name template used with --output-dir, fields: .Dir .Base .Name .Ext .Processor .Index")
+	cmd.Flags().IntVar(&jobs, "jobs", 0, "Number of files processed concurrently (default: number of CPUs)")
+	cmd.Flags().BoolVar(&inPlace, "in-place", false, "Replace the content of the input files with the result")
+	cmd.Flags().StringVar(&backupSuffix, "backup-suffix", "", "With --in-place, keep the original files with this suffix (e.g. .bak)")
+}
+
+// checkProcessorFlags reports combinations of the shared flags which can't work together
+func checkProcessorFlags(args []string) error {
+	switch {
+	case fileMode && stringMode:
+		return fmt.Errorf("--file and --string can't be used together")
+	case stringMode && len(args) != 1:
+		return fmt.Errorf("--string needs exactly one argument")
+	case stringMode && (inPlace || outputDir != ""):
+		return fmt.Errorf("--string can't be used with --in-place or --output-dir")
+	case fileMode && len(args) == 0:
+		return fmt.Errorf("--file needs at least one file")
+	case outputPath != "" && (inPlace || outputDir != "" || toClipboard):
+		return fmt.Errorf("--output can't be used with --in-place, --output-dir or --to-clipboard")
+	case backupSuffix != "" && !inPlace:
+		return fmt.Errorf("--backup-suffix needs --in-place")
+	case inPlace && outputDir != "":
+		return fmt.Errorf("--in-place can't be used with --output-dir")
+	case inPlace && (fromClipboard || toClipboard):
+		return fmt.Errorf("--in-place can't be used with the clipboard")
+	case fromClipboard && len(args) > 0:
+		return fmt.Errorf("--from-clipboard can't be used with an input argument")
+	}
+	return nil
+}
+
+// runProcessor runs p on the input of a processor command and writes the result.
+// The input is the clipboard, a string or files given in args, or stdin.
+// Without --file or --string an argument naming an existing file is read,
+// anything else is used as a string.
+func runProcessor(p processors.Processor, flags []processors.Flag, args []string) error {
+	current.report = newReport(p, flags)
+	if err := checkProcessorFlags(args); err != nil {
+		return usageError(err)
+	}
+
+	if fromClipboard {
+		in, err := utils.ReadClipboard()
+		if err != nil {
+			return inputError(err)
+		}
+		return transformString(p, flags, in)
+	}
+	if stringMode {
+		return transformString(p, flags, args[0])
+	}
+
+	files, batch, err := batchFiles(args)
+	if err != nil {
+		return err
+	}
+	if batch {
+		return runBatch(p, flags, files)
+	}
+
+	switch {
+	case len(args) == 0 && utils.IsTerminal(os.Stdin):
+		// Handle interactive input
+		in, err := utils.ReadTerminated(os.Stdin, terminator)
+		if err != nil {
+			return inputError(err)
+		}
+		return transformString(p, flags, in)
+	case len(args) == 0 || args[0] == "-":
+		// pipes and redirected files are read to EOF
+		return writeResult(func(w io.Writer) (err error) {
+			current.InputBytes, err = transformInput(p, flags, os.Stdin, w)
+			return err
+		})
+	case isFile(args[0]):
+		return writeResult(func(w io.Writer) (err error) {
+			current.InputBytes, err = transformFile(p, flags, args[0], w)
+			return err
+		})
+	default:
+		// Not a file, treat as string input
+		return transformString(p, flags, args[0])
+	}
+}
+
+// transformString runs p on s and writes the result
+func transformString(p processors.Processor, flags []processors.Flag, s string) error {
+	current.InputBytes = int64(len(s))
+	out, err := processors.TransformBytes(p, []byte(s), flags...)
+	if err != nil {
+		return err
+	}
+	return writeOutput(out)
+}
+
+// transformFile runs p on the file path and writes the result to w,
+// it returns the number of bytes read
+func transformFile(p processors.Processor, flags []processors.Flag, path string, w io.Writer) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, inputError(err)
+	}
+	defer file.Close()
+
+	return transformInput(p, flags, file, w)
+}
+
+// transformInput runs p on the content of file and writes the result to w,
+// it returns the number of bytes read.
+// Large regular files are streamed, as is any input of processors which
+// prefer it. Pipes have no size and are read whole for the other processors.
+// Stdin is read without its final line break, like input typed in a terminal.
+func transformInput(p processors.Processor, flags []processors.Flag, file *os.File, w io.Writer) (int64, error) {
+	fi, err := file.Stat()
+	if err != nil {
+		return 0, inputError(err)
+	}
+	large := fi.Mode().IsRegular() && fi.Size() > largeFileThreshold
+	r := &countingReader{r: file}
+	var in io.Reader = r
+	if file == os.Stdin {
+		in = utils.TrimFinalNewline(r)
+	}
+
+	// Use central streaming function for all processors
+	if processors.CanStream(p) && (large || processors.PreferStream(p)) {
+		err := processors.TransformStream(p, in, w, flags...)
+		if err != nil && r.err != nil && errors.Is(err, r.err) {
+			err = inputError(err)
+		}
+		return r.n, err
+	}
+
+	// Use traditional method for small inputs
+	d, err := io.ReadAll(in)
+	if err != nil {
+		return r.n, inputError(err)
+	}
+	out, err := processors.TransformBytes(p, d, flags...)
+	if err != nil {
+		return r.n, err
+	}
+	_, err = w.Write(out)
+	return r.n, err
+}
+
+// countingReader counts the bytes read from r and keeps its read error
+// so input errors can be told apart from transform errors
+type countingReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.n += int64(n)
+	if err != nil && err != io.EOF {
+		c.err = err
+	}
+	return n, err
+}
+
+// writeResult calls write with the destination of the result, with
+// --output-format json the result is kept for the report instead
+func writeResult(write func(w io.Writer) error) error {
+	if hexdump && !jsonOutput() {
+		write = hexdumpTo(write)
+	}
+	if jsonOutput() {
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			return err
+		}
+		current.setResult(buf.Bytes())
+		return nil
+	}
+	return writeTo(write)
+}
+
+// writeTo calls write with the destination of the output: the clipboard
+// with --to-clipboard, the file given with --output or stdout.
+// --output files are replaced atomically once write succeeded, binary
+// output is only written to a terminal with --force.
+func writeTo(write func(w io.Writer) error) error {
+	switch {
+	case toClipboard:
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			return err
+		}
+		return utils.WriteClipboard(buf.String())
+	case outputPath != "" && outputPath != "-":
+		perm := os.FileMode(0o644)
+		if fi, err := os.Stat(outputPath); err == nil {
+			perm = fi.Mode().Perm()
+		}
+		return replaceFile(outputPath, perm, write, nil)
+	case !force && utils.IsTerminal(os.Stdout):
+		g := &textGuard{w: os.Stdout}
+		err := write(g)
+		if err == nil {
+			err = g.Close()
+		}
+		if errors.Is(err, errBinaryOutput) {
+			return usageError(errBinaryOutput)
+		}
+		return err
+	default:
+		return write(os.Stdout)
+	}
+}
+
+// writeOutput writes out to the destination of the result
+func writeOutput(out []byte) error {
+	return writeResult(func(w io.Writer) error {
+		_, err := w.Write(out)
+		return err
+	})
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/abhimanyu003/sttr/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr          string
+	serveMaxBodySize   int64
+	serveTimeout       time.Duration
+	serveMaxTransforms int
+)
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "localhost:8080", "Address to listen on, e.g. :8080 for every interface")
+	serveCmd.Flags().Int64Var(&serveMaxBodySize, "max-body-size", server.DefaultMaxBodySize, "Largest request body accepted, in bytes")
+	serveCmd.Flags().DurationVar(&serveTimeout, "timeout", server.DefaultTimeout, "Time limit of a transformation")
+	serveCmd.Flags().IntVar(&serveMaxTransforms, "max-transforms", 0, "Transformations running at once, timed out ones included (default number of CPUs)")
+	rootCmd.AddCommand(serveCmd)
+}
+
+var serveCmd = &cobra.Command{
+	Use:     "serve",
+	Short:   "Serve the processors as an HTTP API and a browser playground",
+	GroupID: groupCommands,
+	Long: `Serve every processor as an HTTP API:
+
+  GET  /                        the playground, a browser UI like sttr interactive
+  GET  /healthz                 liveness probe
+  GET  /v1/processors           every processor with its flags, as JSON
+  GET  /v1/processors/{name}    a single processor
+  POST /v1/{processor}          run a processor on the request body
+
+Flags are query parameters and the body is the input:
+
+  curl --data-binary @config.json 'localhost:8080/v1/json?indent=true'
+
+With Content-Type: application/json the body is {"input": "...", "flags": {...}}
+and the response {"result": "...", "encoding": "utf-8"}, base64 encoded
+inputs and results have "encoding": "base64".
+
+The defaults of the configuration file apply to the requests too.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userConfigErr != nil {
+			return usageError(userConfigErr)
+		}
+
+		logger := log.New(os.Stderr, "sttr: ", log.LstdFlags)
+		srv := &http.Server{
+			Handler: server.New(server.Options{
+				MaxBodySize:   serveMaxBodySize,
+				Timeout:       serveTimeout,
+				MaxTransforms: serveMaxTransforms,
+				Config:        userConfig,
+				Log:           logger,
+			}),
+			ReadHeaderTimeout: 10 * time.Second,
+			ErrorLog:          logger,
+		}
+
+		ln, err := net.Listen("tcp", serveAddr)
+		if err != nil {
+			return usageError(err)
+		}
+		fmt.Fprintf(os.Stderr, "Listening on http://%s\n", ln.Addr())
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdown, cancel := context.WithTimeout(context.Background(), serveTimeout)
+			defer cancel()
+			srv.Shutdown(shutdown)
+		}()
+
+		if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
@@ -13,9 +13,10 @@ func init() {
 }
 
 var versionCmd = &cobra.Command{
-	Use:   "version",
-	Short: "Print the version of sttr",
-	Long:  `All software has a version (semantic at best). This is sttr's'`,
+	Use:     "version",
+	Short:   "Print the version of sttr",
+	GroupID: groupCommands,
+	Long:    `All software has a version (semantic at best). This is sttr's'`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println(Version)
 	},
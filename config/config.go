@@ -0,0 +1,263 @@
+// Package config reads the sttr user configuration: default flag values
+// per processor and user aliases expanding to processor chains.
+//
+// The configuration is a YAML file, by default
+// $XDG_CONFIG_HOME/sttr/config.yaml:
+//
+//	defaults:
+//	  json:
+//	    indent: true
+//	  bcrypt.number-of-rounds: 12
+//	aliases:
+//	  jwtbody: "base64url-decode,json -i"
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/abhimanyu003/sttr/processors"
+	"github.com/ghodss/yaml"
+)
+
+// Config is the user configuration, the zero value and nil are an empty
+// configuration
+type Config struct {
+	// Path is the file the configuration was read from,
+	// empty when it was not read from a file
+	Path string
+
+	defaults map[string][]processors.Flag
+	aliases  map[string]string
+}
+
+// file is the layout of the configuration file
+type file struct {
+	Defaults map[string]any    `json:"defaults"`
+	Aliases  map[string]string `json:"aliases"`
+}
+
+// Path returns the location of the configuration file: $STTR_CONFIG when
+// set, else sttr/config.yaml in $XDG_CONFIG_HOME or the user config directory
+func Path() (string, error) {
+	if path := os.Getenv("STTR_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		var err error
+		if dir, err = os.UserConfigDir(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, "sttr", "config.yaml"), nil
+}
+
+// Load reads the configuration file, a missing file is an empty configuration
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return &Config{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	c.Path = path
+	return c, nil
+}
+
+// Parse parses a configuration, every default and alias is checked against
+// the registered processors
+func Parse(data []byte) (*Config, error) {
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+
+	c := &Config{
+		defaults: make(map[string][]processors.Flag),
+		aliases:  make(map[string]string),
+	}
+
+	// "json: {indent: true}" and "json.indent: true" are both accepted
+	for key, value := range f.Defaults {
+		if name, flag, ok := strings.Cut(key, "."); ok {
+			if err := c.setDefault(name, flag, value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		flags, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("defaults.%s: expected flag values, e.g. %s: {flag: value}", key, key)
+		}
+		for flag, v := range flags {
+			if err := c.setDefault(key, flag, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for name, spec := range f.Aliases {
+		if name == "" || strings.ContainsAny(name, " \t,") {
+			return nil, fmt.Errorf("aliases: invalid alias name %q", name)
+		}
+		if p, ok := processors.Lookup(name); ok {
+			return nil, fmt.Errorf("aliases.%s: the name is already used by the processor %s", name, p.Name())
+		}
+		c.aliases[name] = spec
+	}
+	for name := range c.aliases {
+		if _, err := c.Alias(name); err != nil {
+			return nil, fmt.Errorf("aliases.%s: %w", name, err)
+		}
+	}
+
+	return c, nil
+}
+
+// setDefault validates and records the default value of a processor flag
+func (c *Config) setDefault(name, flag string, value any) error {
+	p, ok := processors.Lookup(name)
+	if !ok {
+		return fmt.Errorf("defaults.%s: unknown processor", name)
+	}
+	if value == nil {
+		return fmt.Errorf("defaults.%s.%s: missing value", name, flag)
+	}
+
+	var def processors.Flag
+	for _, f := range p.Flags() {
+		if f.Name == flag || f.Short == flag {
+			def = f
+		}
+	}
+	if def.Name == "" {
+		return fmt.Errorf("defaults.%s: unknown flag %s", name, flag)
+	}
+
+	def.Value = value
+	values, err := processors.ParseFlags(p.Flags(), def)
+	if err != nil {
+		return fmt.Errorf("defaults.%s: %w", name, err)
+	}
+	switch def.Type {
+	case processors.FlagBool:
+		def.Value = values.Bool(def.Name)
+	case processors.FlagInt:
+		def.Value = values.Int(def.Name)
+	case processors.FlagUint:
+		def.Value = values.Uint(def.Name)
+	default:
+		def.Value = values.String(def.Name)
+	}
+
+	flags := c.defaults[p.Name()]
+	flags = slices.DeleteFunc(flags, func(f processors.Flag) bool { return f.Name == def.Name })
+	c.defaults[p.Name()] = append(flags, def)
+	return nil
+}
+
+// Defaults returns the flags of the processor named name which have a
+// configured default, sorted by flag name
+func (c *Config) Defaults(name string) []processors.Flag {
+	if c == nil {
+		return nil
+	}
+	flags := slices.Clone(c.defaults[name])
+	slices.SortFunc(flags, func(a, b processors.Flag) int { return strings.Compare(a.Name, b.Name) })
+	return flags
+}
+
+// Processors returns the names of the processors with configured defaults
+func (c *Config) Processors() []string {
+	if c == nil {
+		return nil
+	}
+	names := make([]string, 0, len(c.defaults))
+	for name := range c.defaults {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Aliases returns the names of the user aliases in lexical order
+func (c *Config) Aliases() []string {
+	if c == nil {
+		return nil
+	}
+	names := make([]string, 0, len(c.aliases))
+	for name := range c.aliases {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// AliasSpec returns the chain an alias expands to as written in the file
+func (c *Config) AliasSpec(name string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	spec, ok := c.aliases[name]
+	return spec, ok
+}
+
+// Alias returns the chain the alias name expands to
+func (c *Config) Alias(name string) (processors.Chain, error) {
+	return c.expand(name, nil)
+}
+
+// ParseChain is processors.ParseChain with user aliases expanded and the
+// configured defaults applied to every step
+func (c *Config) ParseChain(spec string) (processors.Chain, error) {
+	return c.parseChain(spec, nil)
+}
+
+func (c *Config) parseChain(spec string, seen []string) (processors.Chain, error) {
+	return processors.ParseChainFunc(spec, func(args []string) (processors.Chain, error) {
+		if _, ok := c.AliasSpec(args[0]); ok {
+			if len(args) > 1 {
+				return nil, fmt.Errorf("aliases don't take flags")
+			}
+			return c.expand(args[0], seen)
+		}
+		name := args[0]
+		if p, ok := processors.Lookup(name); ok {
+			name = p.Name()
+		}
+		step, err := processors.ParseStepDefaults(args, c.Defaults(name))
+		if err != nil {
+			return nil, err
+		}
+		return processors.Chain{step}, nil
+	})
+}
+
+// expand parses the chain of an alias, seen holds the aliases being
+// expanded to report cycles
+func (c *Config) expand(name string, seen []string) (processors.Chain, error) {
+	spec, ok := c.AliasSpec(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown alias %s", name)
+	}
+	if slices.Contains(seen, name) {
+		return nil, fmt.Errorf("alias cycle: %s -> %s", strings.Join(seen, " -> "), name)
+	}
+	return c.parseChain(spec, append(seen, name))
+}
@@ -0,0 +1,222 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string][]any
+		wantErr string
+	}{
+		{
+			name: "Nested and dotted defaults",
+			data: "defaults:\n  json:\n    indent: true\n  bcrypt.number-of-rounds: 12\n  zeropad:\n    p: x\n",
+			want: map[string][]any{
+				"json":    {true},
+				"bcrypt":  {uint(12)},
+				"zeropad": {"x"},
+			},
+		},
+		{
+			name: "Processor alias names are resolved",
+			data: "defaults:\n  b64-dec.raw: true\n",
+			want: map[string][]any{"base64-decode": {true}},
+		},
+		{
+			name:    "Unknown processor",
+			data:    "defaults:\n  nope.x: 1\n",
+			wantErr: "defaults.nope: unknown processor",
+		},
+		{
+			name:    "Unknown flag",
+			data:    "defaults:\n  json.nope: true\n",
+			wantErr: "defaults.json: unknown flag nope",
+		},
+		{
+			name:    "Out of range value",
+			data:    "defaults:\n  bcrypt.number-of-rounds: 99\n",
+			wantErr: "defaults.bcrypt: flag --number-of-rounds: 99 is out of range",
+		},
+		{
+			name:    "Flags are not a map",
+			data:    "defaults:\n  json: true\n",
+			wantErr: "defaults.json: expected flag values",
+		},
+		{
+			name:    "Invalid YAML",
+			data:    "defaults: [",
+			wantErr: "yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Parse([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			got := make(map[string][]any)
+			for _, name := range c.Processors() {
+				for _, f := range c.Defaults(name) {
+					got[name] = append(got[name], f.Value)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() defaults = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Aliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		alias   string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "Alias expands to a chain",
+			data:  "aliases:\n  jwtbody: \"base64url-decode,json -i\"\n",
+			alias: "jwtbody",
+			want:  []string{"base64url-decode", "json -i"},
+		},
+		{
+			name:  "Aliases can use other aliases and get the defaults",
+			data:  "defaults:\n  json.indent: true\naliases:\n  pretty: json\n  both: \"upper,pretty\"\n",
+			alias: "both",
+			want:  []string{"upper", "json -i"},
+		},
+		{
+			name:    "Alias cycle",
+			data:    "aliases:\n  a: b\n  b: a\n",
+			wantErr: "alias cycle",
+		},
+		{
+			name:    "Alias shadowing a processor",
+			data:    "aliases:\n  json: \"json -i\"\n",
+			wantErr: "aliases.json: the name is already used by the processor json",
+		},
+		{
+			name:    "Alias with an unknown processor",
+			data:    "aliases:\n  x: \"upper,nope\"\n",
+			wantErr: "aliases.x: step 2 (nope): unknown processor",
+		},
+		{
+			name:    "Invalid alias name",
+			data:    "aliases:\n  \"a b\": upper\n",
+			wantErr: `invalid alias name "a b"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Parse([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			chain, err := c.Alias(tt.alias)
+			if err != nil {
+				t.Fatalf("Alias() error = %v", err)
+			}
+			var got []string
+			for _, step := range chain {
+				got = append(got, strings.Join(step.Args(), " "))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Alias() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_ParseChain(t *testing.T) {
+	c, err := Parse([]byte("defaults:\n  zeropad.n: 2\naliases:\n  pad: zeropad\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	chain, err := c.ParseChain("pad,zeropad -n 3")
+	if err != nil {
+		t.Fatalf("ParseChain() error = %v", err)
+	}
+	out, err := chain.Transform([]byte("1"))
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if want := "000001"; out != want {
+		t.Errorf("Transform() = %q, want %q", out, want)
+	}
+
+	if _, err := c.ParseChain("pad -n 1"); err == nil || !strings.Contains(err.Error(), "aliases don't take flags") {
+		t.Errorf("ParseChain() error = %v, want aliases don't take flags", err)
+	}
+}
+
+func TestConfig_Nil(t *testing.T) {
+	var c *Config
+	if c.Defaults("json") != nil || c.Aliases() != nil || c.Processors() != nil {
+		t.Error("nil Config should be empty")
+	}
+	chain, err := c.ParseChain("upper")
+	if err != nil || len(chain) != 1 {
+		t.Errorf("ParseChain() = %v, %v", chain, err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("STTR_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	c, err := Load()
+	if err != nil || c.Path != "" {
+		t.Fatalf("Load() without a file = %v, %v", c, err)
+	}
+
+	path := filepath.Join(dir, "sttr", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("aliases:\n  up: upper\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if c.Path != path || !reflect.DeepEqual(c.Aliases(), []string{"up"}) {
+		t.Errorf("Load() = %q %v, want %q [up]", c.Path, c.Aliases(), path)
+	}
+
+	other := filepath.Join(dir, "other.yaml")
+	if err := os.WriteFile(other, []byte("defaults:\n  json.nope: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("STTR_CONFIG", other)
+	if _, err := Load(); err == nil || !strings.HasPrefix(err.Error(), other+":") {
+		t.Errorf("Load() error = %v, want it prefixed by the path", err)
+	}
+}
@@ -0,0 +1,526 @@
+// Package lsp implements enough of the Language Server Protocol over stdio
+// for editors to offer the sttr processors as code actions on the selection.
+//
+// Nothing runs when the code actions are listed. Processors without flags
+// are code actions whose edit replaces the selection with the output of the
+// processor, computed by codeAction/resolve, or sttr.transform commands for
+// clients which can't resolve edits. Processors with flags are always
+// sttr.transform commands taking the flags as arguments, the server applies
+// their edit with workspace/applyEdit. Transformations run while the next
+// messages are read, so $/cancelRequest cancels them. Failures are reported
+// with window/showMessage.
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/abhimanyu003/sttr/config"
+	"github.com/abhimanyu003/sttr/processors"
+	"github.com/abhimanyu003/sttr/rpc"
+)
+
+// CommandTransform is the command replacing a range with the output of a
+// processor, its single argument is a TransformArgs
+const CommandTransform = "sttr.transform"
+
+// codeActionKind is the kind of the code actions, editors may filter on it
+const codeActionKind = "refactor.rewrite"
+
+// codeServerNotInitialized is returned to the requests sent before initialize
+const codeServerNotInitialized = -32002
+
+// Options configure Serve
+type Options struct {
+	// Config gives the default flag values of the processors
+	Config *config.Config
+	// MaxMessageSize is the longest message body accepted, in bytes,
+	// 0 is rpc.DefaultMaxMessageSize
+	MaxMessageSize int
+}
+
+type server struct {
+	opts Options
+
+	mu     sync.Mutex
+	out    io.Writer
+	nextID int
+
+	// jobs are the requests running off the read loop, pending holds
+	// their cancel functions by id key
+	jobs      sync.WaitGroup
+	pendingMu sync.Mutex
+	pending   map[string]context.CancelFunc
+
+	docs        map[string]string
+	encoding    string
+	resolve     bool
+	initialized bool
+	shutdown    bool
+}
+
+// job is the part of a request which runs off the read loop
+type job func(ctx context.Context) (any, *rpc.Error)
+
+// errRequestCancelled answers the requests cancelled by $/cancelRequest
+var errRequestCancelled = &rpc.Error{Code: rpc.CodeRequestCancelled, Message: "request cancelled"}
+
+// Serve answers the messages read from in on out until the exit
+// notification or the end of in, then waits for the running requests
+func Serve(ctx context.Context, in io.Reader, out io.Writer, opts Options) error {
+	s := &server{
+		opts:     opts,
+		out:      out,
+		pending:  make(map[string]context.CancelFunc),
+		docs:     make(map[string]string),
+		encoding: encodingUTF16,
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer s.jobs.Wait()
+
+	maxMessageSize := opts.MaxMessageSize
+	if maxMessageSize <= 0 {
+		maxMessageSize = rpc.DefaultMaxMessageSize
+	}
+	r := bufio.NewReader(in)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		body, err := readMessage(r, maxMessageSize)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			s.replyError(nil, &rpc.Error{Code: rpc.CodeParseError, Message: fmt.Sprintf("parse error: %v", err)})
+			continue
+		}
+		if msg.Method == "" {
+			// a response to workspace/applyEdit or window/showMessage
+			continue
+		}
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit before shutdown")
+			}
+			return nil
+		}
+
+		result, rpcErr := s.handle(ctx, msg)
+		if j, ok := result.(job); ok {
+			s.start(ctx, msg.ID, j)
+			continue
+		}
+		if msg.ID == nil {
+			continue
+		}
+		if rpcErr != nil {
+			s.replyError(msg.ID, rpcErr)
+			continue
+		}
+		s.write(resultResponse{JSONRPC: "2.0", ID: msg.ID, Result: result})
+	}
+}
+
+// start runs j in the background and answers the request id with its
+// result, an id already used by a running request is an error
+func (s *server) start(ctx context.Context, id json.RawMessage, j job) {
+	key, ok := rpc.IDKey(id)
+	if id != nil && !ok {
+		s.replyError(id, &rpc.Error{Code: rpc.CodeInvalidRequest, Message: "invalid request: id must be a string, a number or null"})
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	if key != "" {
+		s.pendingMu.Lock()
+		if _, ok := s.pending[key]; ok {
+			s.pendingMu.Unlock()
+			cancel()
+			s.replyError(id, &rpc.Error{Code: rpc.CodeInvalidRequest, Message: fmt.Sprintf("invalid request: id %s is already used by a running request", id)})
+			return
+		}
+		s.pending[key] = cancel
+		s.pendingMu.Unlock()
+	}
+
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+		defer cancel()
+
+		result, rpcErr := j(ctx)
+		if key != "" {
+			s.pendingMu.Lock()
+			delete(s.pending, key)
+			s.pendingMu.Unlock()
+		}
+		switch {
+		case id == nil:
+		case rpcErr != nil:
+			s.replyError(id, rpcErr)
+		default:
+			s.write(resultResponse{JSONRPC: "2.0", ID: id, Result: result})
+		}
+	}()
+}
+
+// cancel cancels the running request named by the params of $/cancelRequest,
+// unknown and finished requests are ignored
+func (s *server) cancel(raw json.RawMessage) {
+	var params rpc.CancelParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return
+	}
+	key, ok := rpc.IDKey(params.ID)
+	if !ok || key == "" {
+		return
+	}
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	if cancel, ok := s.pending[key]; ok {
+		cancel()
+	}
+}
+
+// handle runs the method of msg, the result of notifications is dropped.
+// Requests running a transformation return a job.
+func (s *server) handle(ctx context.Context, msg message) (any, *rpc.Error) {
+	switch {
+	case msg.Method == "initialize":
+		return s.initialize(msg.Params)
+	case !s.initialized:
+		return nil, &rpc.Error{Code: codeServerNotInitialized, Message: "the server is not initialized"}
+	case s.shutdown:
+		return nil, &rpc.Error{Code: rpc.CodeInvalidRequest, Message: "the server is shut down"}
+	}
+
+	switch msg.Method {
+	case "initialized", "$/setTrace":
+		return nil, nil
+	case "$/cancelRequest":
+		s.cancel(msg.Params)
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := decodeParams(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		s.docs[params.TextDocument.URI] = params.TextDocument.Text
+		return nil, nil
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := decodeParams(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		// the sync is full, the last change is the whole document
+		if n := len(params.ContentChanges); n > 0 {
+			s.docs[params.TextDocument.URI] = params.ContentChanges[n-1].Text
+		}
+		return nil, nil
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := decodeParams(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		delete(s.docs, params.TextDocument.URI)
+		return nil, nil
+	case "textDocument/codeAction":
+		var params codeActionParams
+		if err := decodeParams(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.codeActions(params), nil
+	case "codeAction/resolve":
+		var action CodeAction
+		if err := decodeParams(msg.Params, &action); err != nil {
+			return nil, err
+		}
+		return s.resolveAction(action), nil
+	case "workspace/executeCommand":
+		var params executeCommandParams
+		if err := decodeParams(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.executeCommand(params)
+	}
+	return nil, &rpc.Error{Code: rpc.CodeMethodNotFound, Message: fmt.Sprintf("method not found: %s", msg.Method)}
+}
+
+func (s *server) initialize(raw json.RawMessage) (any, *rpc.Error) {
+	var params initializeParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if slices.Contains(params.Capabilities.General.PositionEncodings, encodingUTF8) {
+		s.encoding = encodingUTF8
+	}
+	if rs := params.Capabilities.TextDocument.CodeAction.ResolveSupport; rs != nil {
+		s.resolve = slices.Contains(rs.Properties, "edit")
+	}
+	s.initialized = true
+
+	return map[string]any{
+		"capabilities": map[string]any{
+			"positionEncoding": s.encoding,
+			// full document sync
+			"textDocumentSync": 1,
+			"codeActionProvider": map[string]any{
+				"codeActionKinds": []string{codeActionKind},
+				"resolveProvider": s.resolve,
+			},
+			"executeCommandProvider": map[string]any{
+				"commands": []string{CommandTransform},
+			},
+		},
+		"serverInfo": map[string]string{"name": "sttr"},
+	}, nil
+}
+
+// codeActions lists the processors applicable to the selection. No
+// processor runs: the edits are computed when the actions are resolved,
+// or when their command is executed for clients which can't resolve them.
+func (s *server) codeActions(params codeActionParams) []CodeAction {
+	actions := make([]CodeAction, 0)
+	if params.Range.Start == params.Range.End || !wantsKind(params.Context.Only) {
+		return actions
+	}
+	if _, err := s.selection(params.TextDocument.URI, params.Range); err != nil {
+		return actions
+	}
+
+	for _, p := range processors.All() {
+		args := TransformArgs{URI: params.TextDocument.URI, Range: params.Range, Processor: p.Name()}
+		if len(p.Flags()) > 0 {
+			actions = append(actions, s.commandActions(p, args)...)
+			continue
+		}
+
+		if !s.resolve {
+			actions = append(actions, commandAction(actionTitle(p, ""), args))
+			continue
+		}
+		actions = append(actions, CodeAction{Title: actionTitle(p, ""), Kind: codeActionKind, Data: &args})
+	}
+	return actions
+}
+
+// commandActions are the actions of a processor with flags: the sttr.transform
+// command with the default flags, and with each Bool flag which is off
+// by default turned on
+func (s *server) commandActions(p processors.Processor, args TransformArgs) []CodeAction {
+	values, err := processors.ParseFlags(p.Flags(), s.opts.Config.Defaults(p.Name())...)
+	if err != nil {
+		return nil
+	}
+	args.Flags = values.Map()
+
+	actions := []CodeAction{commandAction(actionTitle(p, ""), args)}
+	for _, f := range p.Flags() {
+		if f.Type != processors.FlagBool || values.Bool(f.Name) {
+			continue
+		}
+		variant := args
+		variant.Flags = values.Map()
+		variant.Flags[f.Name] = true
+		actions = append(actions, commandAction(actionTitle(p, "--"+f.Name), variant))
+	}
+	return actions
+}
+
+func commandAction(title string, args TransformArgs) CodeAction {
+	return CodeAction{
+		Title:   title,
+		Kind:    codeActionKind,
+		Command: &Command{Title: title, Command: CommandTransform, Arguments: []TransformArgs{args}},
+	}
+}
+
+// actionTitle is "sttr: " followed by the title of p without the name
+// the processor titles end with, e.g. "sttr: Format JSON"
+func actionTitle(p processors.Processor, flag string) string {
+	title := strings.TrimSuffix(processors.TitleOf(p), " ("+p.Name()+")")
+	if flag != "" {
+		title += " (" + flag + ")"
+	}
+	return "sttr: " + title
+}
+
+func wantsKind(only []string) bool {
+	if len(only) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(only, func(kind string) bool {
+		return kind == codeActionKind || strings.HasPrefix(codeActionKind, kind+".")
+	})
+}
+
+// resolveAction returns the job filling the edit of an action listed by
+// codeActions
+func (s *server) resolveAction(action CodeAction) any {
+	if action.Data == nil {
+		return action
+	}
+	args := *action.Data
+	text, err := s.selection(args.URI, args.Range)
+	return job(func(ctx context.Context) (any, *rpc.Error) {
+		if err == nil {
+			var out string
+			if out, err = s.transform(ctx, args, text); err == nil {
+				action.Edit = edit(args, out)
+				return action, nil
+			}
+		}
+		if errors.Is(err, context.Canceled) {
+			return nil, errRequestCancelled
+		}
+		s.showMessage(messageError, fmt.Sprintf("sttr %s: %v", args.Processor, err))
+		return action, nil
+	})
+}
+
+// executeCommand returns the job running sttr.transform and applying its edit
+func (s *server) executeCommand(params executeCommandParams) (any, *rpc.Error) {
+	if params.Command != CommandTransform {
+		return nil, &rpc.Error{Code: rpc.CodeInvalidParams, Message: fmt.Sprintf("unknown command %s", params.Command)}
+	}
+	if len(params.Arguments) != 1 {
+		return nil, &rpc.Error{Code: rpc.CodeInvalidParams, Message: fmt.Sprintf("%s takes a single argument", CommandTransform)}
+	}
+
+	args := params.Arguments[0]
+	text, err := s.selection(args.URI, args.Range)
+	return job(func(ctx context.Context) (any, *rpc.Error) {
+		if err == nil {
+			var out string
+			if out, err = s.transform(ctx, args, text); err == nil {
+				s.request("workspace/applyEdit", applyEditParams{Label: "sttr " + args.Processor, Edit: *edit(args, out)})
+				return nil, nil
+			}
+		}
+		if errors.Is(err, context.Canceled) {
+			return nil, errRequestCancelled
+		}
+		s.showMessage(messageError, fmt.Sprintf("sttr %s: %v", args.Processor, err))
+		return nil, nil
+	}), nil
+}
+
+// transform runs the processor of args on text, the output must be text
+// to replace the selection
+func (s *server) transform(ctx context.Context, args TransformArgs, text string) (string, error) {
+	p, ok := processors.Lookup(args.Processor)
+	if !ok {
+		return "", fmt.Errorf("unknown processor %s", args.Processor)
+	}
+	flags, err := processors.FlagsFromMap(p.Flags(), args.Flags)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := processors.Run(ctx, processors.Request{Processor: p, Flags: flags, Input: text, Defaults: s.opts.Config.Defaults})
+	if err != nil {
+		return "", err
+	}
+	if res.Encoding != "utf-8" {
+		return "", errors.New("the output is binary data")
+	}
+	return res.Result, nil
+}
+
+func (s *server) selection(uri string, r Range) (string, error) {
+	text, ok := s.docs[uri]
+	if !ok {
+		return "", fmt.Errorf("unknown document %s", uri)
+	}
+	return selection(text, r, s.encoding)
+}
+
+func edit(args TransformArgs, out string) *WorkspaceEdit {
+	return &WorkspaceEdit{Changes: map[string][]TextEdit{
+		args.URI: {{Range: args.Range, NewText: out}},
+	}}
+}
+
+func (s *server) showMessage(typ int, msg string) {
+	s.write(outgoing{JSONRPC: "2.0", Method: "window/showMessage", Params: showMessageParams{Type: typ, Message: msg}})
+}
+
+// request sends a request to the client, its response is ignored
+func (s *server) request(method string, params any) {
+	s.mu.Lock()
+	s.nextID++
+	id := "sttr-" + strconv.Itoa(s.nextID)
+	s.mu.Unlock()
+	s.write(outgoing{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+}
+
+func (s *server) replyError(id json.RawMessage, err *rpc.Error) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	s.write(errorResponse{JSONRPC: "2.0", ID: id, Error: err})
+}
+
+// write sends a message with its Content-Length header
+func (s *server) write(v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n", len(body))
+	s.out.Write(body)
+}
+
+// readMessage reads the body of the next message, its headers end with an
+// empty line and must give its Content-Length, at most maxSize bytes
+func readMessage(r *bufio.Reader, maxSize int) ([]byte, error) {
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("reading the message header: %w", err)
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length %q", header.Get("Content-Length"))
+	}
+	if length > maxSize {
+		return nil, fmt.Errorf("message of %d bytes is over the %d bytes limit", length, maxSize)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("reading the message body: %w", err)
+	}
+	return body, nil
+}
+
+func decodeParams(raw json.RawMessage, v any) *rpc.Error {
+	if len(raw) == 0 {
+		return &rpc.Error{Code: rpc.CodeInvalidParams, Message: "params are required"}
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &rpc.Error{Code: rpc.CodeInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+	}
+	return nil
+}
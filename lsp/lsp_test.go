@@ -0,0 +1,305 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abhimanyu003/sttr/config"
+	"github.com/abhimanyu003/sttr/processors"
+	"github.com/abhimanyu003/sttr/rpc"
+)
+
+// session runs the server on the messages and returns the messages it sent
+func session(t *testing.T, opts Options, msgs ...string) []map[string]any {
+	t.Helper()
+	var in bytes.Buffer
+	for _, msg := range msgs {
+		fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(msg), msg)
+	}
+	var out bytes.Buffer
+	if err := Serve(context.Background(), &in, &out, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var sent []map[string]any
+	r := bufio.NewReader(&out)
+	for {
+		body, err := readMessage(r, rpc.DefaultMaxMessageSize)
+		if err != nil {
+			break
+		}
+		var msg map[string]any
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatal(err)
+		}
+		sent = append(sent, msg)
+	}
+	return sent
+}
+
+const (
+	initialize = `{"jsonrpc":"2.0","id":0,"method":"initialize","params":{"capabilities":{}}}`
+	didOpen    = `{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///a","text":"aGk=\n{\"a\":1}\nb c"}}}`
+)
+
+// response returns the response to the request id
+func response(t *testing.T, sent []map[string]any, id float64) map[string]any {
+	t.Helper()
+	for _, msg := range sent {
+		if msg["id"] == id && msg["method"] == nil {
+			return msg
+		}
+	}
+	t.Fatalf("no response to request %v in %v", id, sent)
+	return nil
+}
+
+func titles(actions []any) []string {
+	var titles []string
+	for _, a := range actions {
+		titles = append(titles, a.(map[string]any)["title"].(string))
+	}
+	return titles
+}
+
+func TestServe_CodeActions(t *testing.T) {
+	sent := session(t, Options{}, initialize, didOpen,
+		`{"jsonrpc":"2.0","id":1,"method":"textDocument/codeAction","params":{"textDocument":{"uri":"file:///a"},"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":4}},"context":{}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/codeAction","params":{"textDocument":{"uri":"file:///a"},"range":{"start":{"line":0,"character":1},"end":{"line":0,"character":1}},"context":{}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"textDocument/codeAction","params":{"textDocument":{"uri":"file:///a"},"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":4}},"context":{"only":["quickfix"]}}}`,
+	)
+
+	actions := response(t, sent, 1)["result"].([]any)
+	got := titles(actions)
+	for _, want := range []string{"sttr: Base64 Decode", "sttr: Base64 Decode (--raw)", "sttr: Format JSON (--indent)", "sttr: To Upper case", "sttr: MD5 Sum"} {
+		if !slices.Contains(got, want) {
+			t.Errorf("code actions %q don't contain %q", got, want)
+		}
+	}
+
+	// without resolve support every action is a command, nothing runs
+	// before one is picked
+	for _, a := range actions {
+		action := a.(map[string]any)
+		if action["edit"] != nil || action["command"] == nil {
+			t.Errorf("%v has an edit or no command", action["title"])
+		}
+		switch action["title"] {
+		case "sttr: To Upper case":
+			cmd := action["command"].(map[string]any)
+			args := cmd["arguments"].([]any)[0].(map[string]any)
+			if cmd["command"] != CommandTransform || args["processor"] != "upper" || args["flags"] != nil {
+				t.Errorf("sttr: To Upper case command = %v", cmd)
+			}
+		case "sttr: Base64 Decode (--raw)":
+			cmd := action["command"].(map[string]any)
+			args := cmd["arguments"].([]any)[0].(map[string]any)
+			if cmd["command"] != CommandTransform || args["processor"] != "base64-decode" || args["flags"].(map[string]any)["raw"] != true {
+				t.Errorf("sttr: Base64 Decode (--raw) command = %v", cmd)
+			}
+		}
+	}
+
+	if n := len(response(t, sent, 2)["result"].([]any)); n != 0 {
+		t.Errorf("got %d code actions on an empty selection, want none", n)
+	}
+	if n := len(response(t, sent, 3)["result"].([]any)); n != 0 {
+		t.Errorf("got %d code actions of kind quickfix, want none", n)
+	}
+}
+
+func TestServe_Resolve(t *testing.T) {
+	sent := session(t, Options{},
+		`{"jsonrpc":"2.0","id":0,"method":"initialize","params":{"capabilities":{"general":{"positionEncodings":["utf-8"]},"textDocument":{"codeAction":{"resolveSupport":{"properties":["edit"]}}}}}}`,
+		didOpen,
+		`{"jsonrpc":"2.0","id":1,"method":"textDocument/codeAction","params":{"textDocument":{"uri":"file:///a"},"range":{"start":{"line":2,"character":0},"end":{"line":2,"character":3}},"context":{}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"codeAction/resolve","params":{"title":"sttr: To Snake case","kind":"refactor.rewrite","data":{"uri":"file:///a","range":{"start":{"line":2,"character":0},"end":{"line":2,"character":3}},"processor":"snake"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"codeAction/resolve","params":{"title":"sttr: Hex Decode","kind":"refactor.rewrite","data":{"uri":"file:///a","range":{"start":{"line":2,"character":0},"end":{"line":2,"character":3}},"processor":"hex-decode"}}}`,
+	)
+
+	caps := response(t, sent, 0)["result"].(map[string]any)["capabilities"].(map[string]any)
+	if caps["positionEncoding"] != "utf-8" || caps["codeActionProvider"].(map[string]any)["resolveProvider"] != true {
+		t.Errorf("capabilities = %v", caps)
+	}
+
+	// every processor is listed, edits come when resolved
+	actions := response(t, sent, 1)["result"].([]any)
+	if got := titles(actions); !slices.Contains(got, "sttr: Sort Lines") {
+		t.Errorf("code actions %q don't contain sttr: Sort Lines", got)
+	}
+
+	resolved := response(t, sent, 2)["result"].(map[string]any)
+	edits := resolved["edit"].(map[string]any)["changes"].(map[string]any)["file:///a"].([]any)
+	if text := edits[0].(map[string]any)["newText"]; text != "b_c" {
+		t.Errorf("resolved newText = %v, want b_c", text)
+	}
+
+	if failed := response(t, sent, 3)["result"].(map[string]any); failed["edit"] != nil {
+		t.Errorf("failed action was resolved with an edit: %v", failed)
+	}
+	if !slices.ContainsFunc(sent, func(msg map[string]any) bool {
+		return msg["method"] == "window/showMessage" &&
+			strings.HasPrefix(msg["params"].(map[string]any)["message"].(string), "sttr hex-decode: ")
+	}) {
+		t.Errorf("the failure of hex-decode wasn't shown: %v", sent)
+	}
+}
+
+func TestServe_ExecuteCommand(t *testing.T) {
+	c, err := config.Parse([]byte("defaults:\n  zeropad.n: 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := session(t, Options{Config: c}, initialize, didOpen,
+		`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"file:///a","version":2},"contentChanges":[{"text":"7"}]}}`,
+		`{"jsonrpc":"2.0","id":1,"method":"workspace/executeCommand","params":{"command":"sttr.transform","arguments":[{"uri":"file:///a","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":1}},"processor":"zeropad","flags":{"p":"#"}}]}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"workspace/executeCommand","params":{"command":"sttr.transform","arguments":[{"uri":"file:///a","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":1}},"processor":"zeropad","flags":{"x":1}}]}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	)
+
+	var applied, shown []string
+	for _, msg := range sent {
+		params, _ := msg["params"].(map[string]any)
+		switch msg["method"] {
+		case "workspace/applyEdit":
+			edits := params["edit"].(map[string]any)["changes"].(map[string]any)["file:///a"].([]any)
+			applied = append(applied, edits[0].(map[string]any)["newText"].(string))
+		case "window/showMessage":
+			shown = append(shown, params["message"].(string))
+		}
+	}
+	if !slices.Equal(applied, []string{"#07"}) {
+		t.Errorf("applied edits %q, want [#07]", applied)
+	}
+	if !slices.Equal(shown, []string{"sttr zeropad: unknown flag x"}) {
+		t.Errorf("shown messages %q, want [sttr zeropad: unknown flag x]", shown)
+	}
+	response(t, sent, 3)
+}
+
+func TestServe_Lifecycle(t *testing.T) {
+	sent := session(t, Options{},
+		`{"jsonrpc":"2.0","id":1,"method":"textDocument/codeAction","params":{}}`,
+		initialize,
+		`{"jsonrpc":"2.0","id":2,"method":"nope"}`,
+	)
+	if code := response(t, sent, 1)["error"].(map[string]any)["code"]; code != float64(codeServerNotInitialized) {
+		t.Errorf("error code before initialize = %v, want %d", code, codeServerNotInitialized)
+	}
+	if code := response(t, sent, 2)["error"].(map[string]any)["code"]; code != float64(-32601) {
+		t.Errorf("error code of an unknown method = %v, want -32601", code)
+	}
+
+	var out bytes.Buffer
+	in := strings.NewReader(fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(`{"jsonrpc":"2.0","method":"exit"}`), `{"jsonrpc":"2.0","method":"exit"}`))
+	if err := Serve(context.Background(), in, &out, Options{}); err == nil {
+		t.Error("exit before shutdown didn't fail")
+	}
+}
+
+func TestServe_InvalidContentLength(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		opts   Options
+	}{
+		{name: "Negative", header: "Content-Length: -1"},
+		{name: "Not a number", header: "Content-Length: x"},
+		{name: "Missing", header: "Content-Type: application/vscode-jsonrpc"},
+		{name: "Over the default limit", header: fmt.Sprintf("Content-Length: %d", rpc.DefaultMaxMessageSize+1)},
+		{name: "Huge", header: "Content-Length: 9223372036854775807"},
+		{name: "Over the limit", header: "Content-Length: 11", opts: Options{MaxMessageSize: 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := strings.NewReader(tt.header + "\r\n\r\n{}")
+			if err := Serve(context.Background(), in, io.Discard, tt.opts); err == nil {
+				t.Error("Serve() error = nil, want an invalid message error")
+			}
+		})
+	}
+}
+
+// blockingProcessor doesn't return until release is closed
+type blockingProcessor struct{}
+
+var release chan struct{}
+
+func (p blockingProcessor) Name() string             { return "test-lsp-block" }
+func (p blockingProcessor) Alias() []string          { return nil }
+func (p blockingProcessor) Flags() []processors.Flag { return nil }
+func (p blockingProcessor) Transform(data []byte, _ ...processors.Flag) (string, error) {
+	<-release
+	return string(data), nil
+}
+
+func TestServe_Cancel(t *testing.T) {
+	if _, ok := processors.Lookup("test-lsp-block"); !ok {
+		if err := processors.Register(blockingProcessor{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	release = make(chan struct{})
+	defer close(release)
+
+	run := `{"jsonrpc":"2.0","id":1,"method":"workspace/executeCommand","params":{"command":"sttr.transform","arguments":[{"uri":"file:///a","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":4}},"processor":"test-lsp-block"}]}}`
+	sent := session(t, Options{}, initialize, didOpen, run,
+		// the messages are read while request 1 runs
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/codeAction","params":{"textDocument":{"uri":"file:///a"},"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":4}},"context":{}}}`,
+		`{"jsonrpc":"2.0","id":1.0,"method":"workspace/executeCommand","params":{"command":"sttr.transform","arguments":[{"uri":"file:///a","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":4}},"processor":"upper"}]}}`,
+		`{"jsonrpc":"2.0","method":"$/cancelRequest","params":{"id":1}}`,
+	)
+
+	var codes []any
+	for _, msg := range sent {
+		if msg["method"] == "window/showMessage" || msg["method"] == "workspace/applyEdit" {
+			t.Errorf("cancelled request sent %v", msg)
+		}
+		if msg["id"] == float64(1) {
+			errObj, _ := msg["error"].(map[string]any)
+			codes = append(codes, errObj["code"])
+		}
+	}
+	// the duplicate id is rejected, then request 1 is cancelled
+	if want := []any{float64(rpc.CodeInvalidRequest), float64(rpc.CodeRequestCancelled)}; !slices.Equal(codes, want) {
+		t.Errorf("error codes of the responses to request 1 = %v, want %v", codes, want)
+	}
+	if len(response(t, sent, 2)["result"].([]any)) == 0 {
+		t.Error("no code actions while request 1 was running")
+	}
+}
+
+func TestServe_LargeSelection(t *testing.T) {
+	text := strings.Repeat("aGVsbG8gd29ybGQ=", 1<<20)
+	open, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"method":  "textDocument/didOpen",
+		"params":  map[string]any{"textDocument": map[string]any{"uri": "file:///big", "text": text}},
+	})
+	codeAction := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"textDocument/codeAction","params":{"textDocument":{"uri":"file:///big"},"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":%d}},"context":{}}}`, len(text))
+
+	start := time.Now()
+	sent := session(t, Options{}, initialize, string(open), codeAction)
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("listing the code actions of a %d bytes selection took %v", len(text), elapsed)
+	}
+
+	actions := response(t, sent, 1)["result"].([]any)
+	if len(actions) == 0 {
+		t.Fatal("no code actions on the selection")
+	}
+	for _, a := range actions {
+		if action := a.(map[string]any); action["edit"] != nil {
+			t.Errorf("%v was computed up front", action["title"])
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package lsp
+
+import (
+	"encoding/json"
+
+	"github.com/abhimanyu003/sttr/rpc"
+)
+
+// the subset of the Language Server Protocol used by sttr lsp
+
+// message is any JSON-RPC message: a request, a notification or a response
+// to a request of the server
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpc.Error      `json:"error,omitempty"`
+}
+
+type resultResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result"`
+}
+
+type errorResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Error   *rpc.Error      `json:"error"`
+}
+
+type outgoing struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      string `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// Position is a zero based line and character offset, counted in the
+// position encoding agreed on during initialize
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is the text between Start and End, End excluded
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+type initializeParams struct {
+	Capabilities struct {
+		General struct {
+			PositionEncodings []string `json:"positionEncodings"`
+		} `json:"general"`
+		TextDocument struct {
+			CodeAction struct {
+				ResolveSupport *struct {
+					Properties []string `json:"properties"`
+				} `json:"resolveSupport"`
+			} `json:"codeAction"`
+		} `json:"textDocument"`
+	} `json:"capabilities"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type didOpenParams struct {
+	TextDocument struct {
+		URI  string `json:"uri"`
+		Text string `json:"text"`
+	} `json:"textDocument"`
+}
+
+type didChangeParams struct {
+	TextDocument   textDocumentIdentifier `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type didCloseParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type codeActionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+	Context      struct {
+		Only []string `json:"only"`
+	} `json:"context"`
+}
+
+// CodeAction is offered on the selection, Edit is set up front or once
+// the action is resolved, actions with a Command are applied by the server
+type CodeAction struct {
+	Title   string         `json:"title"`
+	Kind    string         `json:"kind"`
+	Edit    *WorkspaceEdit `json:"edit,omitempty"`
+	Command *Command       `json:"command,omitempty"`
+	Data    *TransformArgs `json:"data,omitempty"`
+}
+
+// Command is run by the client with workspace/executeCommand
+type Command struct {
+	Title     string          `json:"title"`
+	Command   string          `json:"command"`
+	Arguments []TransformArgs `json:"arguments"`
+}
+
+// TransformArgs is the argument of the sttr.transform command: the range of
+// the document replaced by the output of the processor run with Flags
+type TransformArgs struct {
+	URI       string         `json:"uri"`
+	Range     Range          `json:"range"`
+	Processor string         `json:"processor"`
+	Flags     map[string]any `json:"flags,omitempty"`
+}
+
+type executeCommandParams struct {
+	Command   string          `json:"command"`
+	Arguments []TransformArgs `json:"arguments"`
+}
+
+// WorkspaceEdit holds the edits of every document by URI
+type WorkspaceEdit struct {
+	Changes map[string][]TextEdit `json:"changes"`
+}
+
+// TextEdit replaces Range with NewText
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+type applyEditParams struct {
+	Label string        `json:"label"`
+	Edit  WorkspaceEdit `json:"edit"`
+}
+
+// messageError is the error type of window/showMessage
+const messageError = 1
+
+type showMessageParams struct {
+	Type    int    `json:"type"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,71 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// position encodings, utf-16 unless the client offers utf-8
+const (
+	encodingUTF16 = "utf-16"
+	encodingUTF8  = "utf-8"
+)
+
+// offset returns the byte offset of pos in text, characters are counted in
+// UTF-16 code units or bytes depending on encoding. Positions past the end
+// of a line are the end of the line, like the protocol asks, and lines past
+// the end of text are its end.
+func offset(text string, pos Position, encoding string) (int, error) {
+	if pos.Line < 0 || pos.Character < 0 {
+		return 0, fmt.Errorf("invalid position %d:%d", pos.Line, pos.Character)
+	}
+
+	start := 0
+	for line := 0; line < pos.Line; line++ {
+		i := strings.IndexByte(text[start:], '\n')
+		if i < 0 {
+			// past the last line
+			return len(text), nil
+		}
+		start += i + 1
+	}
+	end := len(text)
+	if i := strings.IndexByte(text[start:], '\n'); i >= 0 {
+		end = start + i
+		if end > start && text[end-1] == '\r' {
+			end--
+		}
+	}
+
+	i, units := start, 0
+	for i < end && units < pos.Character {
+		r, size := utf8.DecodeRuneInString(text[i:end])
+		switch {
+		case encoding == encodingUTF8:
+			units += size
+		case r >= 0x10000:
+			units += 2
+		default:
+			units++
+		}
+		i += size
+	}
+	return i, nil
+}
+
+// selection returns the text of r in text
+func selection(text string, r Range, encoding string) (string, error) {
+	start, err := offset(text, r.Start, encoding)
+	if err != nil {
+		return "", err
+	}
+	end, err := offset(text, r.End, encoding)
+	if err != nil {
+		return "", err
+	}
+	if end < start {
+		return "", fmt.Errorf("invalid range, the end is before the start")
+	}
+	return text[start:end], nil
+}
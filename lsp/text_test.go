@@ -0,0 +1,36 @@
+package lsp
+
+import "testing"
+
+func TestSelection(t *testing.T) {
+	text := "a😀b é\r\nline two\nlast"
+
+	tests := []struct {
+		name     string
+		r        Range
+		encoding string
+		want     string
+	}{
+		{name: "UTF-16 surrogate pair", r: Range{Position{0, 1}, Position{0, 4}}, encoding: encodingUTF16, want: "😀b"},
+		{name: "UTF-8 bytes", r: Range{Position{0, 1}, Position{0, 6}}, encoding: encodingUTF8, want: "😀b"},
+		{name: "CRLF is not part of the line", r: Range{Position{0, 4}, Position{0, 99}}, encoding: encodingUTF16, want: " é"},
+		{name: "Across lines", r: Range{Position{0, 5}, Position{1, 4}}, encoding: encodingUTF16, want: "é\r\nline"},
+		{name: "Last line", r: Range{Position{2, 0}, Position{2, 4}}, encoding: encodingUTF16, want: "last"},
+		{name: "Past the end", r: Range{Position{1, 5}, Position{5, 0}}, encoding: encodingUTF16, want: "two\nlast"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selection(text, tt.r, tt.encoding)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("selection() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := selection(text, Range{Position{1, 2}, Position{0, 0}}, encodingUTF16); err == nil {
+		t.Error("selection() with the end before the start didn't fail")
+	}
+}
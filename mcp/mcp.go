@@ -0,0 +1,257 @@
+// Package mcp serves the sttr processors as the tools of a Model Context
+// Protocol server over stdio, so agents can run exact encodings and hashes.
+//
+// Every processor is a tool named after it, taking the input string and
+// the flags of the processor as arguments. Outputs which are non-text,
+// invalid UTF-8 or containing NUL bytes, are returned base64 encoded.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/abhimanyu003/sttr/config"
+	"github.com/abhimanyu003/sttr/processors"
+	"github.com/abhimanyu003/sttr/rpc"
+)
+
+// ProtocolVersions are the supported versions of the protocol, the latest first
+var ProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// the arguments of every tool besides the flags of the processor
+const (
+	argInput    = "input"
+	argEncoding = "encoding"
+)
+
+// Options configure Serve
+type Options struct {
+	// Config gives the default flag values of the processors
+	Config *config.Config
+	// Version is the version of sttr reported to the clients
+	Version string
+}
+
+// Tool describes a processor to the clients
+type Tool struct {
+	Name         string          `json:"name"`
+	Title        string          `json:"title,omitempty"`
+	Description  string          `json:"description"`
+	InputSchema  Schema          `json:"inputSchema"`
+	OutputSchema Schema          `json:"outputSchema"`
+	Annotations  ToolAnnotations `json:"annotations"`
+}
+
+// ToolAnnotations are hints about the behaviour of a tool
+type ToolAnnotations struct {
+	ReadOnlyHint  bool `json:"readOnlyHint"`
+	OpenWorldHint bool `json:"openWorldHint"`
+}
+
+// Schema is the JSON schema of an object
+type Schema struct {
+	Type                 string              `json:"type"`
+	Properties           map[string]Property `json:"properties"`
+	Required             []string            `json:"required"`
+	AdditionalProperties bool                `json:"additionalProperties"`
+}
+
+// Property is the JSON schema of a property of an object
+type Property struct {
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Default     any      `json:"default,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Minimum     *int     `json:"minimum,omitempty"`
+	Maximum     *int     `json:"maximum,omitempty"`
+}
+
+// Output is the structured content of the result of a tool call
+type Output struct {
+	Result string `json:"result"`
+	// Encoding of Result, utf-8 or base64 when it is not text
+	Encoding string `json:"encoding"`
+}
+
+// Content is an item of the content of the result of a tool call
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// CallResult is the result of tools/call, failures of the processor are
+// results with IsError set so the agent can see them
+type CallResult struct {
+	Content           []Content `json:"content"`
+	StructuredContent *Output   `json:"structuredContent,omitempty"`
+	IsError           bool      `json:"isError"`
+}
+
+type initializeParams struct {
+	ProtocolVersion string `json:"protocolVersion"`
+}
+
+type callParams struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// Serve answers the messages read from in on out until in ends
+func Serve(ctx context.Context, in io.Reader, out io.Writer, opts Options) error {
+	s := &server{opts: opts}
+	return rpc.ServeHandler(ctx, in, out, 0, s.handle)
+}
+
+type server struct {
+	opts Options
+}
+
+func (s *server) handle(ctx context.Context, method string, raw json.RawMessage) (any, *rpc.Error) {
+	switch method {
+	case "initialize":
+		var params initializeParams
+		if err := rpc.DecodeParams(raw, &params); err != nil {
+			return nil, err
+		}
+		version := ProtocolVersions[0]
+		if slices.Contains(ProtocolVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{"listChanged": false}},
+			"serverInfo":      map[string]string{"name": "sttr", "version": s.opts.Version},
+		}, nil
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		tools := make([]Tool, 0)
+		for _, p := range processors.All() {
+			tools = append(tools, ToolOf(p))
+		}
+		return map[string]any{"tools": tools}, nil
+	case "tools/call":
+		var params callParams
+		if err := rpc.DecodeParams(raw, &params); err != nil {
+			return nil, err
+		}
+		return s.call(ctx, params)
+	case "notifications/initialized":
+		return nil, nil
+	default:
+		return nil, rpc.NewError(rpc.CodeMethodNotFound, "", fmt.Errorf("method not found: %s", method))
+	}
+}
+
+// ToolOf describes p as a tool, its input schema holds the input and
+// encoding arguments and the flags of p
+func ToolOf(p processors.Processor) Tool {
+	properties := map[string]Property{
+		argInput: {Type: "string", Description: "The text to transform, base64 encoded when encoding is base64"},
+		argEncoding: {
+			Type:        "string",
+			Description: "Encoding of input, base64 to pass binary data",
+			Default:     "utf-8",
+			Enum:        []string{"utf-8", "base64"},
+		},
+	}
+	for _, f := range p.Flags() {
+		if _, ok := properties[f.Name]; ok {
+			continue
+		}
+		prop := Property{Description: f.Desc, Default: f.Value, Enum: f.Choices}
+		switch f.Type {
+		case processors.FlagBool:
+			prop.Type = "boolean"
+		case processors.FlagInt, processors.FlagUint:
+			prop.Type = "integer"
+			if f.Type == processors.FlagUint {
+				prop.Minimum = new(int)
+			}
+			if f.Range != nil {
+				prop.Minimum, prop.Maximum = &f.Range.Min, &f.Range.Max
+			}
+		default:
+			prop.Type = "string"
+		}
+		properties[f.Name] = prop
+	}
+
+	description := processors.DescriptionOf(p)
+	if description == "" {
+		description = processors.TitleOf(p)
+	}
+	return Tool{
+		Name:        p.Name(),
+		Title:       processors.TitleOf(p),
+		Description: description,
+		InputSchema: Schema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{argInput},
+		},
+		OutputSchema: Schema{
+			Type: "object",
+			Properties: map[string]Property{
+				"result": {Type: "string", Description: "The output, base64 encoded when encoding is base64"},
+				"encoding": {
+					Type:        "string",
+					Description: "Encoding of result, base64 when the output is binary data",
+					Enum:        []string{"utf-8", "base64"},
+				},
+			},
+			Required: []string{"result", "encoding"},
+		},
+		Annotations: ToolAnnotations{ReadOnlyHint: true},
+	}
+}
+
+// call runs the processor named by the tool on the input argument
+func (s *server) call(ctx context.Context, params callParams) (any, *rpc.Error) {
+	p, ok := processors.Lookup(params.Name)
+	if !ok {
+		return nil, rpc.NewError(rpc.CodeInvalidParams, "usage", fmt.Errorf("unknown tool %s", params.Name))
+	}
+
+	input, ok := params.Arguments[argInput].(string)
+	if !ok {
+		return failed(fmt.Errorf("the %s argument must be a string", argInput)), nil
+	}
+	encoding, _ := params.Arguments[argEncoding].(string)
+
+	flagArgs := make(map[string]any, len(params.Arguments))
+	for name, value := range params.Arguments {
+		if name != argInput && name != argEncoding {
+			flagArgs[name] = value
+		}
+	}
+	flags, err := processors.FlagsFromMap(p.Flags(), flagArgs)
+	if err != nil {
+		return failed(err), nil
+	}
+
+	res, err := processors.Run(ctx, processors.Request{
+		Processor: p,
+		Flags:     flags,
+		Input:     input,
+		Encoding:  encoding,
+		Defaults:  s.opts.Config.Defaults,
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil, rpc.NewError(rpc.CodeRequestCancelled, "", errors.New("request cancelled"))
+	}
+	if err != nil {
+		return failed(err), nil
+	}
+
+	output := &Output{Result: res.Result, Encoding: res.Encoding}
+	return CallResult{Content: []Content{{Type: "text", Text: output.Result}}, StructuredContent: output}, nil
+}
+
+func failed(err error) CallResult {
+	return CallResult{Content: []Content{{Type: "text", Text: err.Error()}}, IsError: true}
+}
@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/abhimanyu003/sttr/config"
+	"github.com/abhimanyu003/sttr/processors"
+)
+
+// session runs the server on the requests and returns the responses by id
+func session(t *testing.T, opts Options, requests ...string) map[float64]map[string]any {
+	t.Helper()
+	var out strings.Builder
+	if err := Serve(context.Background(), strings.NewReader(strings.Join(requests, "\n")+"\n"), &out, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	responses := make(map[float64]map[string]any)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var resp map[string]any
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatal(err)
+		}
+		responses[resp["id"].(float64)] = resp
+	}
+	return responses
+}
+
+func TestServe_Initialize(t *testing.T) {
+	responses := session(t, Options{Version: "1.2.3"},
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"initialize","params":{"protocolVersion":"1999-01-01","capabilities":{}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+	)
+
+	result := responses[1]["result"].(map[string]any)
+	if result["protocolVersion"] != "2024-11-05" {
+		t.Errorf("protocolVersion = %v, want the supported version asked by the client", result["protocolVersion"])
+	}
+	if info := result["serverInfo"].(map[string]any); info["name"] != "sttr" || info["version"] != "1.2.3" {
+		t.Errorf("serverInfo = %v", info)
+	}
+	if v := responses[2]["result"].(map[string]any)["protocolVersion"]; v != ProtocolVersions[0] {
+		t.Errorf("protocolVersion = %v, want the latest %s for an unknown version", v, ProtocolVersions[0])
+	}
+	if len(responses) != 3 {
+		t.Errorf("got %d responses, want 3, notifications are not answered", len(responses))
+	}
+	if !reflect.DeepEqual(responses[3]["result"], map[string]any{}) {
+		t.Errorf("ping result = %v, want {}", responses[3]["result"])
+	}
+}
+
+func TestToolOf(t *testing.T) {
+	p, _ := processors.Lookup("zeropad")
+	tool := ToolOf(p)
+
+	if tool.Name != "zeropad" || tool.Description != processors.DescriptionOf(p) {
+		t.Errorf("ToolOf() = %q %q", tool.Name, tool.Description)
+	}
+	if !reflect.DeepEqual(tool.InputSchema.Required, []string{"input"}) {
+		t.Errorf("required = %v, want [input]", tool.InputSchema.Required)
+	}
+	n := tool.InputSchema.Properties["number-of-zeros"]
+	if n.Type != "integer" || fmt.Sprint(n.Default) != "5" || n.Minimum == nil || *n.Minimum != 0 || n.Description == "" {
+		t.Errorf("number-of-zeros = %+v", n)
+	}
+	if prefix := tool.InputSchema.Properties["prefix"]; prefix.Type != "string" {
+		t.Errorf("prefix = %+v", prefix)
+	}
+
+	p, _ = processors.Lookup("crc32")
+	poly := ToolOf(p).InputSchema.Properties["polynomial"]
+	if poly.Type != "string" || len(poly.Enum) == 0 || poly.Default != "ieee" {
+		t.Errorf("polynomial = %+v", poly)
+	}
+
+	p, _ = processors.Lookup("json")
+	if indent := ToolOf(p).InputSchema.Properties["indent"]; indent.Type != "boolean" || indent.Default != false {
+		t.Errorf("indent = %+v", indent)
+	}
+}
+
+func TestServe_Tools(t *testing.T) {
+	c, err := config.Parse([]byte("defaults:\n  zeropad.n: 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	responses := session(t, Options{Config: c},
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"md5","arguments":{"input":"hello"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"zeropad","arguments":{"input":"7","prefix":"#"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"hex-decode","arguments":{"input":"00ff"}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"hex-encode","arguments":{"input":"AP8=","encoding":"base64"}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"json","arguments":{"input":"nope"}}}`,
+		`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"upper","arguments":{"input":"a","x":1}}}`,
+		`{"jsonrpc":"2.0","id":8,"method":"tools/call","params":{"name":"nope","arguments":{"input":"a"}}}`,
+		`{"jsonrpc":"2.0","id":9,"method":"tools/call","params":{"name":"base64-decode","arguments":{"input":"AAE="}}}`,
+	)
+
+	tools := responses[1]["result"].(map[string]any)["tools"].([]any)
+	if len(tools) < len(processors.List) {
+		t.Errorf("tools/list returned %d tools, want at least %d", len(tools), len(processors.List))
+	}
+
+	tests := []struct {
+		id           float64
+		wantText     string
+		wantEncoding string
+		wantError    bool
+	}{
+		{id: 2, wantText: "5d41402abc4b2a76b9719d911017c592", wantEncoding: "utf-8"},
+		{id: 3, wantText: "#07", wantEncoding: "utf-8"},
+		{id: 4, wantText: "AP8=", wantEncoding: "base64"},
+		{id: 5, wantText: "00ff", wantEncoding: "utf-8"},
+		{id: 6, wantText: "invalid character 'o' in literal null (expecting 'u')", wantError: true},
+		{id: 7, wantText: "unknown flag x", wantError: true},
+		{id: 9, wantText: "AAE=", wantEncoding: "base64"},
+	}
+	for _, tt := range tests {
+		result := responses[tt.id]["result"].(map[string]any)
+		text := result["content"].([]any)[0].(map[string]any)["text"]
+		if text != tt.wantText || result["isError"] != tt.wantError {
+			t.Errorf("call %v = %q, isError %v, want %q, isError %v", tt.id, text, result["isError"], tt.wantText, tt.wantError)
+		}
+		if tt.wantEncoding != "" && result["structuredContent"].(map[string]any)["encoding"] != tt.wantEncoding {
+			t.Errorf("call %v structuredContent = %v, want encoding %s", tt.id, result["structuredContent"], tt.wantEncoding)
+		}
+	}
+
+	if code := responses[8]["error"].(map[string]any)["code"]; code != float64(-32602) {
+		t.Errorf("unknown tool error code = %v, want -32602", code)
+	}
+}
@@ -10,6 +10,23 @@ import (
 // ASCII85Encoding encodes plain text to Ascii85 (aka Base85)
 type ASCII85Encoding struct{}
 
+// Implement StreamingProcessor interface with a stateful encoder
+func (p ASCII85Encoding) CanStream() bool {
+	return true
+}
+
+func (p ASCII85Encoding) PreferStream() bool {
+	return true
+}
+
+func (p ASCII85Encoding) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
+	encoder := ascii85.NewEncoder(writer)
+	if _, err := io.Copy(encoder, reader); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
 func (p ASCII85Encoding) Name() string {
 	return "ascii85-encode"
 }
@@ -45,6 +62,14 @@ func (p ASCII85Encoding) Description() string {
 	return "Encode your text to Ascii85 ( Base85 )"
 }
 
+func (p ASCII85Encoding) Category() string {
+	return CategoryEncoding
+}
+
+func (p ASCII85Encoding) Tags() []string {
+	return []string{"base85"}
+}
+
 func (p ASCII85Encoding) FilterValue() string {
 	return p.Title()
 }
@@ -52,6 +77,20 @@ func (p ASCII85Encoding) FilterValue() string {
 // ASCII85Decoding decodes Ascii85 (aka Base85) to plain text.
 type ASCII85Decoding struct{}
 
+// Implement StreamingProcessor interface with a stateful decoder
+func (p ASCII85Decoding) CanStream() bool {
+	return true
+}
+
+func (p ASCII85Decoding) PreferStream() bool {
+	return true
+}
+
+func (p ASCII85Decoding) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
+	_, err := io.Copy(writer, ascii85.NewDecoder(reader))
+	return err
+}
+
 func (p ASCII85Decoding) Name() string {
 	return "ascii85-decode"
 }
@@ -60,14 +99,20 @@ func (p ASCII85Decoding) Alias() []string {
 	return []string{"ascii85-decoding", "base85-decode", "b85-decode"}
 }
 
-func (p ASCII85Decoding) Transform(data []byte, _ ...Flag) (string, error) {
+func (p ASCII85Decoding) Transform(data []byte, f ...Flag) (string, error) {
+	out, err := p.TransformBytes(data, f...)
+	return string(out), err
+}
+
+// Implement BinaryProcessor interface, the output is arbitrary bytes
+func (p ASCII85Decoding) TransformBytes(data []byte, _ ...Flag) ([]byte, error) {
 	decoder := ascii85.NewDecoder(bytes.NewReader(data))
 	buf, err := io.ReadAll(decoder)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return string(buf), nil
+	return buf, nil
 }
 
 func (p ASCII85Decoding) Flags() []Flag {
@@ -83,6 +128,14 @@ func (p ASCII85Decoding) Description() string {
 	return "Decode your text to Ascii85 ( Base85 ) text"
 }
 
+func (p ASCII85Decoding) Category() string {
+	return CategoryEncoding
+}
+
+func (p ASCII85Decoding) Tags() []string {
+	return []string{"base85"}
+}
+
 func (p ASCII85Decoding) FilterValue() string {
 	return p.Title()
 }
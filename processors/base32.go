@@ -3,11 +3,22 @@ package processors
 import (
 	"encoding/base32"
 	"fmt"
+	"io"
 )
 
 // Base32Encoding encodes plain text to Base32 string.
 type Base32Encoding struct{}
 
+// Implement ConfigurableStreamingProcessor interface for chunked processing
+func (p Base32Encoding) GetStreamingConfig() StreamingConfig {
+	return StreamingConfig{
+		ChunkSize:    64 * 1024, // 64KB chunks
+		BufferOutput: false,     // Can encode whole blocks directly
+		LineByLine:   false,
+		BlockSize:    5, // 5 bytes encode to 8 characters
+	}
+}
+
 func (p Base32Encoding) Name() string {
 	return "base32-encode"
 }
@@ -33,6 +44,10 @@ func (p Base32Encoding) Description() string {
 	return "Encode your text to Base32"
 }
 
+func (p Base32Encoding) Category() string {
+	return CategoryEncoding
+}
+
 func (p Base32Encoding) FilterValue() string {
 	return p.Title()
 }
@@ -40,6 +55,20 @@ func (p Base32Encoding) FilterValue() string {
 // Base32Decode decodes string from Base32 string to plain text.
 type Base32Decode struct{}
 
+// Implement StreamingProcessor interface with a stateful decoder
+func (p Base32Decode) CanStream() bool {
+	return true
+}
+
+func (p Base32Decode) PreferStream() bool {
+	return true
+}
+
+func (p Base32Decode) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
+	_, err := io.Copy(writer, base32.NewDecoder(base32.StdEncoding, reader))
+	return err
+}
+
 func (p Base32Decode) Name() string {
 	return "base32-decode"
 }
@@ -48,9 +77,15 @@ func (p Base32Decode) Alias() []string {
 	return []string{"b32-dec", "b32-decode"}
 }
 
-func (p Base32Decode) Transform(data []byte, _ ...Flag) (string, error) {
+func (p Base32Decode) Transform(data []byte, f ...Flag) (string, error) {
+	out, err := p.TransformBytes(data, f...)
+	return string(out), err
+}
+
+// Implement BinaryProcessor interface, the output is arbitrary bytes
+func (p Base32Decode) TransformBytes(data []byte, _ ...Flag) ([]byte, error) {
 	decodedString, err := base32.StdEncoding.DecodeString(string(data))
-	return string(decodedString), err
+	return decodedString, err
 }
 
 func (p Base32Decode) Flags() []Flag {
@@ -66,6 +101,10 @@ func (p Base32Decode) Description() string {
 	return "Decode your base32 text"
 }
 
+func (p Base32Decode) Category() string {
+	return CategoryEncoding
+}
+
 func (p Base32Decode) FilterValue() string {
 	return p.Title()
 }
@@ -3,6 +3,7 @@ package processors
 import (
 	"encoding/base64"
 	"fmt"
+	"io"
 )
 
 var base64RawFlag = Flag{
@@ -13,22 +14,39 @@ var base64RawFlag = Flag{
 	Type:  FlagBool,
 }
 
-func checkBase64RawFlag(f []Flag) bool {
-	raw := false
-	for _, flag := range f {
-		if flag.Name == "raw" || flag.Short == "r" {
-			r, ok := flag.Value.(bool)
-			if ok {
-				raw = r
-			}
-		}
+// base64Encoding returns the encoding selected by the raw flag
+func base64Encoding(f []Flag, url bool) (*base64.Encoding, error) {
+	flags, err := ParseFlags([]Flag{base64RawFlag}, f...)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := flags.Bool("raw")
+	switch {
+	case url && raw:
+		return base64.RawURLEncoding, nil
+	case url:
+		return base64.URLEncoding, nil
+	case raw:
+		return base64.RawStdEncoding, nil
+	default:
+		return base64.StdEncoding, nil
 	}
-	return raw
 }
 
 // Base64Encode encodes plain text to Base64 string.
 type Base64Encode struct{}
 
+// Implement ConfigurableStreamingProcessor interface for chunked processing
+func (p Base64Encode) GetStreamingConfig() StreamingConfig {
+	return StreamingConfig{
+		ChunkSize:    64 * 1024, // 64KB chunks
+		BufferOutput: false,     // Can encode whole blocks directly
+		LineByLine:   false,
+		BlockSize:    3, // 3 bytes encode to 4 characters
+	}
+}
+
 func (p Base64Encode) Name() string {
 	return "base64-encode"
 }
@@ -38,10 +56,11 @@ func (p Base64Encode) Alias() []string {
 }
 
 func (p Base64Encode) Transform(data []byte, f ...Flag) (string, error) {
-	if checkBase64RawFlag(f) {
-		return base64.RawStdEncoding.EncodeToString(data), nil
+	encoding, err := base64Encoding(f, false)
+	if err != nil {
+		return "", err
 	}
-	return base64.StdEncoding.EncodeToString(data), nil
+	return encoding.EncodeToString(data), nil
 }
 
 func (p Base64Encode) Flags() []Flag {
@@ -57,6 +76,10 @@ func (p Base64Encode) Description() string {
 	return "Encode your text to Base64"
 }
 
+func (p Base64Encode) Category() string {
+	return CategoryEncoding
+}
+
 func (p Base64Encode) FilterValue() string {
 	return p.Title()
 }
@@ -64,6 +87,24 @@ func (p Base64Encode) FilterValue() string {
 // Base64Decode decodes string from Base64 string to plain text.
 type Base64Decode struct{}
 
+// Implement StreamingProcessor interface with a stateful decoder
+func (p Base64Decode) CanStream() bool {
+	return true
+}
+
+func (p Base64Decode) PreferStream() bool {
+	return true
+}
+
+func (p Base64Decode) TransformStream(reader io.Reader, writer io.Writer, opts ...Flag) error {
+	encoding, err := base64Encoding(opts, false)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(writer, base64.NewDecoder(encoding, reader))
+	return err
+}
+
 func (p Base64Decode) Name() string {
 	return "base64-decode"
 }
@@ -73,14 +114,18 @@ func (p Base64Decode) Alias() []string {
 }
 
 func (p Base64Decode) Transform(data []byte, f ...Flag) (string, error) {
-	var decodedString []byte
-	var err error
-	if checkBase64RawFlag(f) {
-		decodedString, err = base64.RawStdEncoding.DecodeString(string(data))
-	} else {
-		decodedString, err = base64.StdEncoding.DecodeString(string(data))
+	out, err := p.TransformBytes(data, f...)
+	return string(out), err
+}
+
+// Implement BinaryProcessor interface, the output is arbitrary bytes
+func (p Base64Decode) TransformBytes(data []byte, f ...Flag) ([]byte, error) {
+	encoding, err := base64Encoding(f, false)
+	if err != nil {
+		return nil, err
 	}
-	return string(decodedString), err
+	decodedString, err := encoding.DecodeString(string(data))
+	return decodedString, err
 }
 
 func (p Base64Decode) Flags() []Flag {
@@ -96,6 +141,10 @@ func (p Base64Decode) Description() string {
 	return "Decode your Base64 text"
 }
 
+func (p Base64Decode) Category() string {
+	return CategoryEncoding
+}
+
 func (p Base64Decode) FilterValue() string {
 	return p.Title()
 }
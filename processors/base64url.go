@@ -3,11 +3,22 @@ package processors
 import (
 	"encoding/base64"
 	"fmt"
+	"io"
 )
 
 // Base64URLEncode encodes plain text to Base64 URL string.
 type Base64URLEncode struct{}
 
+// Implement ConfigurableStreamingProcessor interface for chunked processing
+func (p Base64URLEncode) GetStreamingConfig() StreamingConfig {
+	return StreamingConfig{
+		ChunkSize:    64 * 1024, // 64KB chunks
+		BufferOutput: false,     // Can encode whole blocks directly
+		LineByLine:   false,
+		BlockSize:    3, // 3 bytes encode to 4 characters
+	}
+}
+
 func (p Base64URLEncode) Name() string {
 	return "base64url-encode"
 }
@@ -17,10 +28,11 @@ func (p Base64URLEncode) Alias() []string {
 }
 
 func (p Base64URLEncode) Transform(data []byte, f ...Flag) (string, error) {
-	if checkBase64RawFlag(f) {
-		return base64.RawURLEncoding.EncodeToString(data), nil
+	encoding, err := base64Encoding(f, true)
+	if err != nil {
+		return "", err
 	}
-	return base64.URLEncoding.EncodeToString(data), nil
+	return encoding.EncodeToString(data), nil
 }
 
 func (p Base64URLEncode) Flags() []Flag {
@@ -36,6 +48,14 @@ func (p Base64URLEncode) Description() string {
 	return "Encode your text to Base64 with URL Safe"
 }
 
+func (p Base64URLEncode) Category() string {
+	return CategoryEncoding
+}
+
+func (p Base64URLEncode) Tags() []string {
+	return []string{"jwt"}
+}
+
 func (p Base64URLEncode) FilterValue() string {
 	return p.Title()
 }
@@ -43,6 +63,24 @@ func (p Base64URLEncode) FilterValue() string {
 // Base64URLDecode decodes Base64 URL string to plain text.
 type Base64URLDecode struct{}
 
+// Implement StreamingProcessor interface with a stateful decoder
+func (p Base64URLDecode) CanStream() bool {
+	return true
+}
+
+func (p Base64URLDecode) PreferStream() bool {
+	return true
+}
+
+func (p Base64URLDecode) TransformStream(reader io.Reader, writer io.Writer, opts ...Flag) error {
+	encoding, err := base64Encoding(opts, true)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(writer, base64.NewDecoder(encoding, reader))
+	return err
+}
+
 func (p Base64URLDecode) Name() string {
 	return "base64url-decode"
 }
@@ -52,14 +90,18 @@ func (p Base64URLDecode) Alias() []string {
 }
 
 func (p Base64URLDecode) Transform(data []byte, f ...Flag) (string, error) {
-	var decodedString []byte
-	var err error
-	if checkBase64RawFlag(f) {
-		decodedString, err = base64.RawURLEncoding.DecodeString(string(data))
-	} else {
-		decodedString, err = base64.URLEncoding.DecodeString(string(data))
+	out, err := p.TransformBytes(data, f...)
+	return string(out), err
+}
+
+// Implement BinaryProcessor interface, the output is arbitrary bytes
+func (p Base64URLDecode) TransformBytes(data []byte, f ...Flag) ([]byte, error) {
+	encoding, err := base64Encoding(f, true)
+	if err != nil {
+		return nil, err
 	}
-	return string(decodedString), err
+	decodedString, err := encoding.DecodeString(string(data))
+	return decodedString, err
 }
 
 func (p Base64URLDecode) Flags() []Flag {
@@ -75,6 +117,14 @@ func (p Base64URLDecode) Description() string {
 	return "Decode your Base64 text with URL Safe"
 }
 
+func (p Base64URLDecode) Category() string {
+	return CategoryEncoding
+}
+
+func (p Base64URLDecode) Tags() []string {
+	return []string{"jwt"}
+}
+
 func (p Base64URLDecode) FilterValue() string {
 	return p.Title()
 }
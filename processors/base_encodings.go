@@ -24,14 +24,11 @@ func (p CrockfordBase32Encode) Alias() []string {
 }
 
 func (p CrockfordBase32Encode) Transform(data []byte, f ...Flag) (string, error) {
-	var checksum bool = false
-	for _, flag := range f {
-		if flag.Short == "c" {
-			if c, ok := flag.Value.(bool); ok {
-				checksum = c
-			}
-		}
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return "", err
 	}
+	checksum := flags.Bool("checksum")
 
 	encoded := encodeCrockfordBase32(data)
 
@@ -64,6 +61,10 @@ func (p CrockfordBase32Encode) Description() string {
 	return "Encode your text to Crockford Base32"
 }
 
+func (p CrockfordBase32Encode) Category() string {
+	return CategoryEncoding
+}
+
 func (p CrockfordBase32Encode) FilterValue() string {
 	return p.Title()
 }
@@ -79,14 +80,17 @@ func (p CrockfordBase32Decode) Alias() []string {
 }
 
 func (p CrockfordBase32Decode) Transform(data []byte, f ...Flag) (string, error) {
-	var verify bool = false
-	for _, flag := range f {
-		if flag.Short == "v" {
-			if v, ok := flag.Value.(bool); ok {
-				verify = v
-			}
-		}
+	out, err := p.TransformBytes(data, f...)
+	return string(out), err
+}
+
+// Implement BinaryProcessor interface, the output is arbitrary bytes
+func (p CrockfordBase32Decode) TransformBytes(data []byte, f ...Flag) ([]byte, error) {
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return nil, err
 	}
+	verify := flags.Bool("verify")
 
 	input := strings.ToUpper(string(data))
 
@@ -97,25 +101,25 @@ func (p CrockfordBase32Decode) Transform(data []byte, f ...Flag) (string, error)
 
 		decoded, err := decodeCrockfordBase32(dataStr)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
 		expectedChecksum := calculateCrockfordChecksum(decoded)
 		actualChecksum := strings.Index(crockfordAlphabet, checksumChar)
 
 		if actualChecksum == -1 || actualChecksum != expectedChecksum {
-			return "", fmt.Errorf("checksum verification failed")
+			return nil, fmt.Errorf("checksum verification failed")
 		}
 
-		return string(decoded), nil
+		return decoded, nil
 	}
 
 	decoded, err := decodeCrockfordBase32(input)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return string(decoded), nil
+	return decoded, nil
 }
 
 func (p CrockfordBase32Decode) Flags() []Flag {
@@ -138,6 +142,10 @@ func (p CrockfordBase32Decode) Description() string {
 	return "Decode your Crockford Base32 text"
 }
 
+func (p CrockfordBase32Decode) Category() string {
+	return CategoryEncoding
+}
+
 func (p CrockfordBase32Decode) FilterValue() string {
 	return p.Title()
 }
@@ -153,16 +161,12 @@ func (p Base58Encode) Alias() []string {
 }
 
 func (p Base58Encode) Transform(data []byte, f ...Flag) (string, error) {
-	var check bool = false
-	for _, flag := range f {
-		if flag.Short == "c" {
-			if c, ok := flag.Value.(bool); ok {
-				check = c
-			}
-		}
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return "", err
 	}
 
-	if check {
+	if flags.Bool("check") {
 
 		return encodeBase58Check(data), nil
 	}
@@ -190,6 +194,10 @@ func (p Base58Encode) Description() string {
 	return "Encode your text to Base58"
 }
 
+func (p Base58Encode) Category() string {
+	return CategoryEncoding
+}
+
 func (p Base58Encode) FilterValue() string {
 	return p.Title()
 }
@@ -205,30 +213,32 @@ func (p Base58Decode) Alias() []string {
 }
 
 func (p Base58Decode) Transform(data []byte, f ...Flag) (string, error) {
-	var check bool = false
-	for _, flag := range f {
-		if flag.Short == "c" {
-			if c, ok := flag.Value.(bool); ok {
-				check = c
-			}
-		}
+	out, err := p.TransformBytes(data, f...)
+	return string(out), err
+}
+
+// Implement BinaryProcessor interface, the output is arbitrary bytes
+func (p Base58Decode) TransformBytes(data []byte, f ...Flag) ([]byte, error) {
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return nil, err
 	}
 
-	if check {
+	if flags.Bool("check") {
 
 		decoded, err := decodeBase58Check(string(data))
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		return string(decoded), nil
+		return decoded, nil
 	}
 
 	decoded, err := decodeBase58(string(data))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return string(decoded), nil
+	return decoded, nil
 }
 
 func (p Base58Decode) Flags() []Flag {
@@ -251,6 +261,10 @@ func (p Base58Decode) Description() string {
 	return "Decode your Base58 text"
 }
 
+func (p Base58Decode) Category() string {
+	return CategoryEncoding
+}
+
 func (p Base58Decode) FilterValue() string {
 	return p.Title()
 }
@@ -266,14 +280,11 @@ func (p Base62Encode) Alias() []string {
 }
 
 func (p Base62Encode) Transform(data []byte, f ...Flag) (string, error) {
-	var prefix string = ""
-	for _, flag := range f {
-		if flag.Short == "p" {
-			if p, ok := flag.Value.(string); ok {
-				prefix = p
-			}
-		}
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return "", err
 	}
+	prefix := flags.String("prefix")
 
 	encoded := encodeBase62(data)
 
@@ -304,6 +315,10 @@ func (p Base62Encode) Description() string {
 	return "Encode your text to Base62"
 }
 
+func (p Base62Encode) Category() string {
+	return CategoryEncoding
+}
+
 func (p Base62Encode) FilterValue() string {
 	return p.Title()
 }
@@ -318,7 +333,13 @@ func (p Base62Decode) Alias() []string {
 	return []string{"b62-dec", "b62-decode"}
 }
 
-func (p Base62Decode) Transform(data []byte, _ ...Flag) (string, error) {
+func (p Base62Decode) Transform(data []byte, f ...Flag) (string, error) {
+	out, err := p.TransformBytes(data, f...)
+	return string(out), err
+}
+
+// Implement BinaryProcessor interface, the output is arbitrary bytes
+func (p Base62Decode) TransformBytes(data []byte, _ ...Flag) ([]byte, error) {
 	input := string(data)
 
 	if strings.Contains(input, "_") {
@@ -330,10 +351,10 @@ func (p Base62Decode) Transform(data []byte, _ ...Flag) (string, error) {
 
 	decoded, err := decodeBase62(input)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return string(decoded), nil
+	return decoded, nil
 }
 
 func (p Base62Decode) Flags() []Flag {
@@ -348,6 +369,10 @@ func (p Base62Decode) Description() string {
 	return "Decode your Base62 text"
 }
 
+func (p Base62Decode) Category() string {
+	return CategoryEncoding
+}
+
 func (p Base62Decode) FilterValue() string {
 	return p.Title()
 }
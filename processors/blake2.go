@@ -1,8 +1,8 @@
 package processors
 
 import (
-	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 
 	"golang.org/x/crypto/blake2b"
@@ -22,32 +22,26 @@ func (p BLAKE2b) PreferStream() bool {
 }
 
 func (p BLAKE2b) TransformStream(reader io.Reader, writer io.Writer, opts ...Flag) error {
-	var size uint = 64 // Default BLAKE2b size
-	for _, flag := range opts {
-		if flag.Short == "s" {
-			if s, ok := flag.Value.(uint); ok {
-				size = s
-			}
-		}
-	}
-
-	if size < 1 || size > 64 {
-		return fmt.Errorf("BLAKE2b size must be between 1 and 64 bytes")
-	}
-
-	hasher, err := blake2b.New(int(size), nil)
+	hasher, err := p.newHash(opts...)
 	if err != nil {
 		return err
 	}
+	return hashStream(hasher, reader, writer)
+}
 
-	_, err = io.Copy(hasher, reader)
+// newHash returns a BLAKE2b hash with the digest size selected in f
+func (p BLAKE2b) newHash(f ...Flag) (hash.Hash, error) {
+	flags, err := ParseFlags(p.Flags(), f...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	result := hex.EncodeToString(hasher.Sum(nil))
-	_, err = writer.Write([]byte(result))
-	return err
+	return blake2b.New(int(flags.Uint("size")), nil)
+}
+
+// Implement DigestProcessor interface
+func (p BLAKE2b) IsDigest() bool {
+	return true
 }
 
 func (p BLAKE2b) Name() string {
@@ -59,26 +53,11 @@ func (p BLAKE2b) Alias() []string {
 }
 
 func (p BLAKE2b) Transform(data []byte, f ...Flag) (string, error) {
-	var size uint = 64 // Default BLAKE2b size
-	for _, flag := range f {
-		if flag.Short == "s" {
-			if s, ok := flag.Value.(uint); ok {
-				size = s
-			}
-		}
-	}
-
-	if size < 1 || size > 64 {
-		return "", fmt.Errorf("BLAKE2b size must be between 1 and 64 bytes")
-	}
-
-	hasher, err := blake2b.New(int(size), nil)
+	hasher, err := p.newHash(f...)
 	if err != nil {
 		return "", err
 	}
-
-	hasher.Write(data)
-	return hex.EncodeToString(hasher.Sum(nil)), nil
+	return hashHex(hasher, data), nil
 }
 
 func (p BLAKE2b) Flags() []Flag {
@@ -89,6 +68,7 @@ func (p BLAKE2b) Flags() []Flag {
 			Desc:  "Hash size in bytes (1-64)",
 			Value: uint(64),
 			Type:  FlagUint,
+			Range: &FlagRange{Min: 1, Max: blake2b.Size},
 		},
 	}
 }
@@ -101,6 +81,14 @@ func (p BLAKE2b) Description() string {
 	return "Get the BLAKE2b hash of your text"
 }
 
+func (p BLAKE2b) Category() string {
+	return CategoryHashing
+}
+
+func (p BLAKE2b) Tags() []string {
+	return []string{"digest"}
+}
+
 func (p BLAKE2b) FilterValue() string {
 	return p.Title()
 }
@@ -117,20 +105,17 @@ func (p BLAKE2s) PreferStream() bool {
 	return true
 }
 
-func (p BLAKE2s) TransformStream(reader io.Reader, writer io.Writer, opts ...Flag) error {
+func (p BLAKE2s) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
 	hasher, err := blake2s.New256(nil)
 	if err != nil {
 		return err
 	}
+	return hashStream(hasher, reader, writer)
+}
 
-	_, err = io.Copy(hasher, reader)
-	if err != nil {
-		return err
-	}
-
-	result := hex.EncodeToString(hasher.Sum(nil))
-	_, err = writer.Write([]byte(result))
-	return err
+// Implement DigestProcessor interface
+func (p BLAKE2s) IsDigest() bool {
+	return true
 }
 
 func (p BLAKE2s) Name() string {
@@ -146,9 +131,7 @@ func (p BLAKE2s) Transform(data []byte, _ ...Flag) (string, error) {
 	if err != nil {
 		return "", err
 	}
-
-	hasher.Write(data)
-	return hex.EncodeToString(hasher.Sum(nil)), nil
+	return hashHex(hasher, data), nil
 }
 
 func (p BLAKE2s) Flags() []Flag {
@@ -163,6 +146,14 @@ func (p BLAKE2s) Description() string {
 	return "Get the BLAKE2s hash of your text"
 }
 
+func (p BLAKE2s) Category() string {
+	return CategoryHashing
+}
+
+func (p BLAKE2s) Tags() []string {
+	return []string{"digest"}
+}
+
 func (p BLAKE2s) FilterValue() string {
 	return p.Title()
 }
@@ -24,6 +24,7 @@ func TestBLAKE2b_Command(t *testing.T) {
 				Desc:  "Hash size in bytes (1-64)",
 				Value: uint(64),
 				Type:  FlagUint,
+				Range: &FlagRange{Min: 1, Max: 64},
 			},
 		},
 		name:  "blake2b",
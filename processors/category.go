@@ -0,0 +1,104 @@
+package processors
+
+import (
+	"slices"
+	"strings"
+)
+
+// Categories processors can declare by implementing Category() string,
+// they group the commands in the help, the interactive UI and the docs
+const (
+	CategoryEncoding    = "encoding"
+	CategoryHashing     = "hashing"
+	CategoryCase        = "case"
+	CategoryLines       = "lines"
+	CategoryExtraction  = "extraction"
+	CategoryDataFormats = "data-formats"
+	CategoryColor       = "color"
+	CategoryGenerators  = "generators"
+	// CategoryOther is the category of processors which don't declare one
+	CategoryOther = "other"
+)
+
+// Categories lists the categories in display order
+var Categories = []string{
+	CategoryEncoding,
+	CategoryHashing,
+	CategoryCase,
+	CategoryLines,
+	CategoryExtraction,
+	CategoryDataFormats,
+	CategoryColor,
+	CategoryGenerators,
+	CategoryOther,
+}
+
+var categoryTitles = map[string]string{
+	CategoryEncoding:    "Encoding",
+	CategoryHashing:     "Hashing",
+	CategoryCase:        "Case",
+	CategoryLines:       "Lines",
+	CategoryExtraction:  "Extraction",
+	CategoryDataFormats: "Data formats",
+	CategoryColor:       "Color",
+	CategoryGenerators:  "Generators",
+	CategoryOther:       "Other",
+}
+
+// CategoryOf returns the Category of a processor, CategoryOther when the
+// processor doesn't implement it
+func CategoryOf(p Processor) string {
+	if c, ok := p.(interface{ Category() string }); ok && c.Category() != "" {
+		return c.Category()
+	}
+	return CategoryOther
+}
+
+// CategoryTitle returns the display name of a category
+func CategoryTitle(category string) string {
+	if t, ok := categoryTitles[category]; ok {
+		return t
+	}
+	if category == "" {
+		return ""
+	}
+	return strings.ToUpper(category[:1]) + strings.ReplaceAll(category[1:], "-", " ")
+}
+
+// CompareCategories orders categories like Categories, unknown ones come
+// before CategoryOther in lexical order
+func CompareCategories(a, b string) int {
+	rank := func(c string) int {
+		if i := slices.Index(Categories, c); i >= 0 && c != CategoryOther {
+			return i
+		}
+		if c == CategoryOther {
+			return len(Categories)
+		}
+		return len(Categories) - 1
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra - rb
+	}
+	return strings.Compare(a, b)
+}
+
+// TagsOf returns the Tags of a processor, extra search terms for the
+// processor, or nil when the processor doesn't implement it
+func TagsOf(p Processor) []string {
+	if t, ok := p.(interface{ Tags() []string }); ok {
+		return t.Tags()
+	}
+	return nil
+}
+
+// ByCategory returns the processors of category in registration order
+func ByCategory(category string) []Processor {
+	var ps []Processor
+	for _, p := range All() {
+		if CategoryOf(p) == category {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
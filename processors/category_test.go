@@ -0,0 +1,59 @@
+package processors
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCategoryOf(t *testing.T) {
+	if got := CategoryOf(MD5{}); got != CategoryHashing {
+		t.Errorf("CategoryOf() = %v, want %v", got, CategoryHashing)
+	}
+	if got := CategoryOf(registryTestProcessor{name: "plain-name"}); got != CategoryOther {
+		t.Errorf("CategoryOf() = %v, want %v", got, CategoryOther)
+	}
+	for _, p := range All() {
+		if c := CategoryOf(p); !slices.Contains(Categories, c) {
+			t.Errorf("CategoryOf(%s) = %v, not in Categories", p.Name(), c)
+		}
+	}
+}
+
+func TestCategoryTitle(t *testing.T) {
+	tests := map[string]string{
+		CategoryDataFormats: "Data formats",
+		CategoryOther:       "Other",
+		"my-things":         "My things",
+		"":                  "",
+	}
+	for category, want := range tests {
+		if got := CategoryTitle(category); got != want {
+			t.Errorf("CategoryTitle(%q) = %q, want %q", category, got, want)
+		}
+	}
+}
+
+func TestCompareCategories(t *testing.T) {
+	got := []string{CategoryOther, "zz", CategoryColor, "aa", CategoryEncoding}
+	slices.SortFunc(got, CompareCategories)
+	want := []string{CategoryEncoding, CategoryColor, "aa", "zz", CategoryOther}
+	if !slices.Equal(got, want) {
+		t.Errorf("sorted categories = %v, want %v", got, want)
+	}
+}
+
+func TestTagsOf(t *testing.T) {
+	if got := TagsOf(FormatJSON{}); !slices.Contains(got, "pretty") {
+		t.Errorf("TagsOf() = %v, want it to contain pretty", got)
+	}
+	if got := TagsOf(registryTestProcessor{name: "plain-name"}); got != nil {
+		t.Errorf("TagsOf() = %v, want nil", got)
+	}
+}
+
+func TestByCategory(t *testing.T) {
+	got := ByCategory(CategoryColor)
+	if len(got) != 1 || got[0].Name() != "hex-rgb" {
+		t.Errorf("ByCategory() = %v, want [hex-rgb]", got)
+	}
+}
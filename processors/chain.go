@@ -0,0 +1,290 @@
+package processors
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// ChainStep is a single processor invocation inside a Chain,
+// Flags holds every flag of the processor with its parsed value
+type ChainStep struct {
+	Processor Processor
+	Flags     []Flag
+}
+
+// Chain is an ordered list of processors, the output of each step
+// is used as the input of the next one
+type Chain []ChainStep
+
+// ParseChain parses a comma separated list of steps, each step being a
+// processor name or alias followed by its flags.
+// Example: "base64-decode,json --indent,json-yaml".
+// Values containing commas or spaces can be quoted.
+func ParseChain(spec string) (Chain, error) {
+	return ParseChainFunc(spec, func(args []string) (Chain, error) {
+		step, err := ParseStep(args)
+		if err != nil {
+			return nil, err
+		}
+		return Chain{step}, nil
+	})
+}
+
+// ParseChainFunc is ParseChain with every step resolved by parse,
+// which may expand a single step into several ones
+func ParseChainFunc(spec string, parse func(args []string) (Chain, error)) (Chain, error) {
+	steps, err := splitChain(spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("empty chain")
+	}
+
+	chain := make(Chain, 0, len(steps))
+	for i, args := range steps {
+		if len(args) == 0 {
+			return nil, fmt.Errorf("step %d: missing processor name", i+1)
+		}
+		step, err := parse(args)
+		if err != nil {
+			return nil, fmt.Errorf("step %d (%s): %w", i+1, args[0], err)
+		}
+		chain = append(chain, step...)
+	}
+
+	return chain, nil
+}
+
+// ParseStep resolves args[0] to a processor and parses the remaining
+// args against the processor Flags()
+func ParseStep(args []string) (ChainStep, error) {
+	return ParseStepDefaults(args, nil)
+}
+
+// ParseStepDefaults is ParseStep with defaults replacing the default
+// value of the processor flags they name
+func ParseStepDefaults(args []string, defaults []Flag) (ChainStep, error) {
+	if len(args) == 0 {
+		return ChainStep{}, fmt.Errorf("missing processor name")
+	}
+
+	p, ok := Lookup(args[0])
+	if !ok {
+		return ChainStep{}, fmt.Errorf("unknown processor")
+	}
+
+	values, err := ParseFlags(p.Flags(), defaults...)
+	if err != nil {
+		return ChainStep{}, err
+	}
+
+	fs := pflag.NewFlagSet(p.Name(), pflag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	defs := p.Flags()
+	for _, flag := range defs {
+		flag.Value = values.values[flag.Name]
+		if err := defineFlag(fs, flag); err != nil {
+			return ChainStep{}, err
+		}
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return ChainStep{}, err
+	}
+	if fs.NArg() > 0 {
+		return ChainStep{}, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	flags := make([]Flag, 0, len(defs))
+	for _, flag := range defs {
+		f := fs.Lookup(flag.Name)
+		value, err := flagValue(flag.Type, f.Value.String())
+		if err != nil {
+			return ChainStep{}, fmt.Errorf("flag --%s: %w", flag.Name, err)
+		}
+		flag.Value = value
+		flags = append(flags, flag)
+	}
+	if err := ValidateFlags(p, flags...); err != nil {
+		return ChainStep{}, err
+	}
+
+	return ChainStep{Processor: p, Flags: flags}, nil
+}
+
+// Transform runs every step of the chain on data
+func (c Chain) Transform(data []byte) (string, error) {
+	out := string(data)
+	for i, step := range c {
+		var err error
+		out, err = step.Processor.Transform([]byte(out), step.Flags...)
+		if err != nil {
+			return "", fmt.Errorf("step %d (%s): %w", i+1, step.Processor.Name(), err)
+		}
+	}
+	return out, nil
+}
+
+// Args returns the command line arguments equivalent to the step, the
+// processor name followed by the flags whose value differs from the default
+func (s ChainStep) Args() []string {
+	args := []string{s.Processor.Name()}
+
+	defaults, err := ParseFlags(s.Processor.Flags())
+	if err != nil {
+		return args
+	}
+	for _, flag := range s.Flags {
+		def, ok := findFlag(s.Processor.Flags(), flag)
+		if !ok || flag.Value == nil {
+			continue
+		}
+		value, err := convertFlagValue(def, flag.Value)
+		if err != nil || fmt.Sprint(value) == fmt.Sprint(defaults.values[def.Name]) {
+			continue
+		}
+
+		name := "--" + def.Name
+		if def.Short != "" {
+			name = "-" + def.Short
+		}
+		switch {
+		case def.Type == FlagBool && value == true:
+			args = append(args, name)
+		case def.Type == FlagBool:
+			args = append(args, "--"+def.Name+"=false")
+		default:
+			args = append(args, name, fmt.Sprint(value))
+		}
+	}
+
+	return args
+}
+
+// CommandLine returns the shell pipeline equivalent to the chain,
+// e.g. "sttr base64-decode | sttr json -i"
+func (c Chain) CommandLine() string {
+	cmds := make([]string, 0, len(c))
+	for _, step := range c {
+		args := step.Args()
+		for i, arg := range args {
+			args[i] = shellQuote(arg)
+		}
+		cmds = append(cmds, "sttr "+strings.Join(args, " "))
+	}
+	return strings.Join(cmds, " | ")
+}
+
+// shellQuote quotes s for POSIX shells when it contains special characters
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.,:/=+@%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// defineFlag adds flag to fs, using the flag Value as default
+func defineFlag(fs *pflag.FlagSet, flag Flag) error {
+	def := ""
+	if flag.Value != nil {
+		def = fmt.Sprint(flag.Value)
+	}
+
+	switch flag.Type {
+	case FlagBool:
+		b, _ := strconv.ParseBool(def)
+		fs.BoolP(flag.Name, flag.Short, b, flag.Desc)
+	case FlagInt:
+		i, _ := strconv.Atoi(def)
+		fs.IntP(flag.Name, flag.Short, i, flag.Desc)
+	case FlagUint:
+		u, _ := strconv.ParseUint(def, 10, 0)
+		fs.UintP(flag.Name, flag.Short, uint(u), flag.Desc)
+	case FlagString:
+		fs.StringP(flag.Name, flag.Short, def, flag.Desc)
+	default:
+		return fmt.Errorf("flag --%s has unsupported type %q", flag.Name, flag.Type)
+	}
+	return nil
+}
+
+// flagValue converts the string representation of a flag to its Go type
+func flagValue(t FlagType, s string) (any, error) {
+	switch t {
+	case FlagBool:
+		return strconv.ParseBool(s)
+	case FlagInt:
+		return strconv.Atoi(s)
+	case FlagUint:
+		u, err := strconv.ParseUint(s, 10, 0)
+		return uint(u), err
+	default:
+		return s, nil
+	}
+}
+
+// splitChain splits spec into steps and each step into shell like words,
+// single and double quotes group words and commas outside quotes end a step
+func splitChain(spec string) ([][]string, error) {
+	var (
+		steps  [][]string
+		words  []string
+		word   strings.Builder
+		inWord bool
+		quote  rune
+	)
+
+	endWord := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+
+	runes := []rune(spec)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' && i+1 < len(runes) {
+				i++
+				word.WriteRune(runes[i])
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '\\' && i+1 < len(runes):
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case r == ',':
+			endWord()
+			steps = append(steps, words)
+			words = nil
+		case r == ' ' || r == '\t' || r == '\n':
+			endWord()
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", spec)
+	}
+	endWord()
+	if len(words) > 0 || len(steps) > 0 {
+		steps = append(steps, words)
+	}
+
+	return steps, nil
+}
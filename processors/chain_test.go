@@ -0,0 +1,309 @@
+package processors
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseChain(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      string
+		wantNames []string
+		wantErr   string
+	}{
+		{
+			name:      "Single step",
+			spec:      "upper",
+			wantNames: []string{"upper"},
+		},
+		{
+			name:      "Multiple steps with flags",
+			spec:      "base64-decode, json --indent ,json-yaml",
+			wantNames: []string{"base64-decode", "json", "json-yaml"},
+		},
+		{
+			name:      "Steps resolved by alias",
+			spec:      "b64-dec,json-yml",
+			wantNames: []string{"base64-decode", "json-yaml"},
+		},
+		{
+			name:    "Empty chain",
+			spec:    "  ",
+			wantErr: "empty chain",
+		},
+		{
+			name:    "Empty step",
+			spec:    "upper,,lower",
+			wantErr: "step 2: missing processor name",
+		},
+		{
+			name:    "Unknown processor",
+			spec:    "upper,nope",
+			wantErr: "step 2 (nope): unknown processor",
+		},
+		{
+			name:    "Unknown flag",
+			spec:    "upper,json --nope",
+			wantErr: "step 2 (json): unknown flag: --nope",
+		},
+		{
+			name:    "Unexpected argument",
+			spec:    "json -i extra",
+			wantErr: `step 1 (json): unexpected argument "extra"`,
+		},
+		{
+			name:    "Unterminated quote",
+			spec:    `remove-spaces -s "`,
+			wantErr: "unterminated quote",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseChain(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseChain() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ParseChain() error = %v", err)
+				return
+			}
+
+			names := make([]string, 0, len(got))
+			for _, step := range got {
+				names = append(names, step.Processor.Name())
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("ParseChain() = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestParseStep_Flags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []Flag
+	}{
+		{
+			name: "Defaults are used when no flags are given",
+			args: []string{"zeropad"},
+			want: []Flag{
+				{Name: "number-of-zeros", Short: "n", Desc: "Number of zeros to be padded", Type: FlagUint, Value: uint(5)},
+				{Name: "prefix", Short: "p", Desc: "The number get prefixed with this", Type: FlagString, Value: ""},
+			},
+		},
+		{
+			name: "Short and long flags",
+			args: []string{"zeropad", "-n", "2", "--prefix=x"},
+			want: []Flag{
+				{Name: "number-of-zeros", Short: "n", Desc: "Number of zeros to be padded", Type: FlagUint, Value: uint(2)},
+				{Name: "prefix", Short: "p", Desc: "The number get prefixed with this", Type: FlagString, Value: "x"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStep(tt.args)
+			if err != nil {
+				t.Errorf("ParseStep() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got.Flags, tt.want) {
+				t.Errorf("ParseStep() flags = %v, want %v", got.Flags, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStepDefaults(t *testing.T) {
+	defaults := []Flag{{Name: "number-of-zeros", Value: 3}, {Name: "prefix", Value: "x"}}
+
+	tests := []struct {
+		name     string
+		args     []string
+		defaults []Flag
+		want     []any
+		wantErr  string
+	}{
+		{
+			name:     "Defaults replace the processor defaults",
+			args:     []string{"zeropad"},
+			defaults: defaults,
+			want:     []any{uint(3), "x"},
+		},
+		{
+			name:     "Flags override the defaults",
+			args:     []string{"zeropad", "-n", "7"},
+			defaults: defaults,
+			want:     []any{uint(7), "x"},
+		},
+		{
+			name:     "Invalid default",
+			args:     []string{"zeropad"},
+			defaults: []Flag{{Name: "number-of-zeros", Value: "many"}},
+			wantErr:  "flag --number-of-zeros",
+		},
+		{
+			name:     "Unknown default",
+			args:     []string{"zeropad"},
+			defaults: []Flag{{Name: "nope", Value: true}},
+			wantErr:  "unknown flag --nope",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStepDefaults(tt.args, tt.defaults)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseStepDefaults() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ParseStepDefaults() error = %v", err)
+				return
+			}
+			values := make([]any, 0, len(got.Flags))
+			for _, f := range got.Flags {
+				values = append(values, f.Value)
+			}
+			if !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("ParseStepDefaults() values = %v, want %v", values, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseChainFunc(t *testing.T) {
+	expand := func(args []string) (Chain, error) {
+		if args[0] == "twice" {
+			return ParseChain("upper,reverse")
+		}
+		step, err := ParseStep(args)
+		return Chain{step}, err
+	}
+
+	chain, err := ParseChainFunc("twice,lower", expand)
+	if err != nil {
+		t.Fatalf("ParseChainFunc() error = %v", err)
+	}
+	var names []string
+	for _, step := range chain {
+		names = append(names, step.Processor.Name())
+	}
+	if want := []string{"upper", "reverse", "lower"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ParseChainFunc() steps = %v, want %v", names, want)
+	}
+}
+
+func TestChain_Transform(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "Decode and format JSON",
+			spec:  "base64-decode,json --indent",
+			input: "eyJhIjoxfQ==",
+			want:  "{\n  \"a\": 1\n}",
+		},
+		{
+			name:  "Decode JSON and convert to YAML",
+			spec:  "base64-decode,json,json-yaml",
+			input: "eyJhIjoxfQ==",
+			want:  "a: 1\n",
+		},
+		{
+			name:  "Quoted flag value with comma",
+			spec:  `remove-spaces -s ",",upper`,
+			input: "a b c",
+			want:  "A,B,C",
+		},
+		{
+			name:    "Failing step is reported",
+			spec:    "upper,base64-decode",
+			input:   "not base64!",
+			wantErr: "step 2 (base64-decode):",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := ParseChain(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseChain() error = %v", err)
+			}
+			got, err := chain.Transform([]byte(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Transform() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Transform() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Transform() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChain_CommandLine(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want string
+	}{
+		{
+			name: "Defaults are omitted",
+			spec: "base64-decode,json",
+			want: "sttr base64-decode | sttr json",
+		},
+		{
+			name: "Bool flag",
+			spec: "base64-decode,json --indent",
+			want: "sttr base64-decode | sttr json -i",
+		},
+		{
+			name: "Bool flag turned off",
+			spec: "escape-quotes --double-quote=false",
+			want: "sttr escape-quotes --double-quote=false",
+		},
+		{
+			name: "Values are quoted",
+			spec: `zeropad -n 3 -p "a b",remove-spaces -s "'"`,
+			want: `sttr zeropad -n 3 -p 'a b' | sttr remove-spaces -s ''\'''`,
+		},
+		{
+			name: "Default value given explicitly",
+			spec: "crc32 -p ieee",
+			want: "sttr crc32",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := ParseChain(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseChain() error = %v", err)
+			}
+			if got := chain.CommandLine(); got != tt.want {
+				t.Errorf("CommandLine() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,13 +2,37 @@ package processors
 
 import (
 	"fmt"
+	"hash"
 	"hash/adler32"
 	"hash/crc32"
+	"io"
 )
 
 // CRC32 generates CRC32 checksum
 type CRC32 struct{}
 
+// Implement StreamingProcessor interface
+func (p CRC32) CanStream() bool {
+	return true
+}
+
+func (p CRC32) PreferStream() bool {
+	return true
+}
+
+func (p CRC32) TransformStream(reader io.Reader, writer io.Writer, opts ...Flag) error {
+	h, err := p.newHash(opts...)
+	if err != nil {
+		return err
+	}
+	return hashStream(h, reader, writer)
+}
+
+// Implement DigestProcessor interface
+func (p CRC32) IsDigest() bool {
+	return true
+}
+
 func (p CRC32) Name() string {
 	return "crc32"
 }
@@ -18,39 +42,41 @@ func (p CRC32) Alias() []string {
 }
 
 func (p CRC32) Transform(data []byte, f ...Flag) (string, error) {
-	var polynomial string = "ieee"
-	for _, flag := range f {
-		if flag.Short == "p" {
-			if pol, ok := flag.Value.(string); ok {
-				polynomial = pol
-			}
-		}
+	h, err := p.newHash(f...)
+	if err != nil {
+		return "", err
+	}
+	return hashHex(h, data), nil
+}
+
+// newHash returns a CRC32 hash using the polynomial selected in f
+func (p CRC32) newHash(f ...Flag) (hash.Hash32, error) {
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return nil, err
 	}
 
-	var table *crc32.Table
-	switch polynomial {
+	switch polynomial := flags.String("polynomial"); polynomial {
 	case "ieee":
-		table = crc32.IEEETable
+		return crc32.NewIEEE(), nil
 	case "castagnoli":
-		table = crc32.MakeTable(crc32.Castagnoli)
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli)), nil
 	case "koopman":
-		table = crc32.MakeTable(crc32.Koopman)
+		return crc32.New(crc32.MakeTable(crc32.Koopman)), nil
 	default:
-		return "", fmt.Errorf("unsupported polynomial: %s (supported: ieee, castagnoli, koopman)", polynomial)
+		return nil, fmt.Errorf("unsupported polynomial: %s (supported: ieee, castagnoli, koopman)", polynomial)
 	}
-
-	checksum := crc32.Checksum(data, table)
-	return fmt.Sprintf("%08x", checksum), nil
 }
 
 func (p CRC32) Flags() []Flag {
 	return []Flag{
 		{
-			Name:  "polynomial",
-			Short: "p",
-			Desc:  "CRC32 polynomial (ieee, castagnoli, koopman)",
-			Value: "ieee",
-			Type:  FlagString,
+			Name:    "polynomial",
+			Short:   "p",
+			Desc:    "CRC32 polynomial (ieee, castagnoli, koopman)",
+			Value:   "ieee",
+			Type:    FlagString,
+			Choices: []string{"ieee", "castagnoli", "koopman"},
 		},
 	}
 }
@@ -63,6 +89,14 @@ func (p CRC32) Description() string {
 	return "Get the CRC32 checksum of your text"
 }
 
+func (p CRC32) Category() string {
+	return CategoryHashing
+}
+
+func (p CRC32) Tags() []string {
+	return []string{"checksum"}
+}
+
 func (p CRC32) FilterValue() string {
 	return p.Title()
 }
@@ -70,6 +104,24 @@ func (p CRC32) FilterValue() string {
 // Adler32 generates Adler32 checksum
 type Adler32 struct{}
 
+// Implement StreamingProcessor interface
+func (p Adler32) CanStream() bool {
+	return true
+}
+
+func (p Adler32) PreferStream() bool {
+	return true
+}
+
+func (p Adler32) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
+	return hashStream(adler32.New(), reader, writer)
+}
+
+// Implement DigestProcessor interface
+func (p Adler32) IsDigest() bool {
+	return true
+}
+
 func (p Adler32) Name() string {
 	return "adler32"
 }
@@ -79,8 +131,7 @@ func (p Adler32) Alias() []string {
 }
 
 func (p Adler32) Transform(data []byte, _ ...Flag) (string, error) {
-	checksum := adler32.Checksum(data)
-	return fmt.Sprintf("%08x", checksum), nil
+	return hashHex(adler32.New(), data), nil
 }
 
 func (p Adler32) Flags() []Flag {
@@ -95,6 +146,14 @@ func (p Adler32) Description() string {
 	return "Get the Adler32 checksum of your text"
 }
 
+func (p Adler32) Category() string {
+	return CategoryHashing
+}
+
+func (p Adler32) Tags() []string {
+	return []string{"checksum"}
+}
+
 func (p Adler32) FilterValue() string {
 	return p.Title()
 }
@@ -19,11 +19,12 @@ func TestCRC32_Command(t *testing.T) {
 		filterValue: "CRC32 Checksum (crc32)",
 		flags: []Flag{
 			{
-				Name:  "polynomial",
-				Short: "p",
-				Desc:  "CRC32 polynomial (ieee, castagnoli, koopman)",
-				Value: "ieee",
-				Type:  FlagString,
+				Name:    "polynomial",
+				Short:   "p",
+				Desc:    "CRC32 polynomial (ieee, castagnoli, koopman)",
+				Value:   "ieee",
+				Type:    FlagString,
+				Choices: []string{"ieee", "castagnoli", "koopman"},
 			},
 		},
 		name:  "crc32",
@@ -5,8 +5,8 @@ import (
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
-	"encoding/hex"
 	"fmt"
+	"io"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -23,6 +23,24 @@ func (p MD5) GetStreamingConfig() StreamingConfig {
 	}
 }
 
+// Implement StreamingProcessor interface
+func (p MD5) CanStream() bool {
+	return true
+}
+
+func (p MD5) PreferStream() bool {
+	return true
+}
+
+func (p MD5) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
+	return hashStream(md5.New(), reader, writer)
+}
+
+// Implement DigestProcessor interface
+func (p MD5) IsDigest() bool {
+	return true
+}
+
 func (p MD5) Name() string {
 	return "md5"
 }
@@ -32,10 +50,7 @@ func (p MD5) Alias() []string {
 }
 
 func (p MD5) Transform(data []byte, _ ...Flag) (string, error) {
-	hasher := md5.New()
-	hasher.Write(data)
-
-	return hex.EncodeToString(hasher.Sum(nil)), nil
+	return hashHex(md5.New(), data), nil
 }
 
 func (p MD5) Flags() []Flag {
@@ -51,6 +66,14 @@ func (p MD5) Description() string {
 	return "Get the MD5 checksum of your text"
 }
 
+func (p MD5) Category() string {
+	return CategoryHashing
+}
+
+func (p MD5) Tags() []string {
+	return []string{"checksum", "digest"}
+}
+
 func (p MD5) FilterValue() string {
 	return p.Title()
 }
@@ -67,6 +90,24 @@ func (p SHA1) GetStreamingConfig() StreamingConfig {
 	}
 }
 
+// Implement StreamingProcessor interface
+func (p SHA1) CanStream() bool {
+	return true
+}
+
+func (p SHA1) PreferStream() bool {
+	return true
+}
+
+func (p SHA1) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
+	return hashStream(sha1.New(), reader, writer)
+}
+
+// Implement DigestProcessor interface
+func (p SHA1) IsDigest() bool {
+	return true
+}
+
 func (p SHA1) Name() string {
 	return "sha1"
 }
@@ -76,11 +117,7 @@ func (p SHA1) Alias() []string {
 }
 
 func (p SHA1) Transform(data []byte, _ ...Flag) (string, error) {
-	h := sha1.New()
-	h.Write(data)
-	bs := h.Sum(nil)
-
-	return fmt.Sprintf("%x", bs), nil
+	return hashHex(sha1.New(), data), nil
 }
 
 func (p SHA1) Flags() []Flag {
@@ -96,6 +133,14 @@ func (p SHA1) Description() string {
 	return "Get the SHA-1 checksum of your text"
 }
 
+func (p SHA1) Category() string {
+	return CategoryHashing
+}
+
+func (p SHA1) Tags() []string {
+	return []string{"checksum", "digest"}
+}
+
 func (p SHA1) FilterValue() string {
 	return p.Title()
 }
@@ -112,6 +157,24 @@ func (p SHA256) GetStreamingConfig() StreamingConfig {
 	}
 }
 
+// Implement StreamingProcessor interface
+func (p SHA256) CanStream() bool {
+	return true
+}
+
+func (p SHA256) PreferStream() bool {
+	return true
+}
+
+func (p SHA256) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
+	return hashStream(sha256.New(), reader, writer)
+}
+
+// Implement DigestProcessor interface
+func (p SHA256) IsDigest() bool {
+	return true
+}
+
 func (p SHA256) Name() string {
 	return "sha256"
 }
@@ -121,11 +184,7 @@ func (p SHA256) Alias() []string {
 }
 
 func (p SHA256) Transform(data []byte, _ ...Flag) (string, error) {
-	h := sha256.New()
-	h.Write(data)
-	bs := h.Sum(nil)
-
-	return fmt.Sprintf("%x", bs), nil
+	return hashHex(sha256.New(), data), nil
 }
 
 func (p SHA256) Flags() []Flag {
@@ -141,6 +200,14 @@ func (p SHA256) Description() string {
 	return "Get the SHA-256 checksum of your text"
 }
 
+func (p SHA256) Category() string {
+	return CategoryHashing
+}
+
+func (p SHA256) Tags() []string {
+	return []string{"checksum", "digest", "sha2"}
+}
+
 func (p SHA256) FilterValue() string {
 	return p.Title()
 }
@@ -157,6 +224,24 @@ func (p SHA512) GetStreamingConfig() StreamingConfig {
 	}
 }
 
+// Implement StreamingProcessor interface
+func (p SHA512) CanStream() bool {
+	return true
+}
+
+func (p SHA512) PreferStream() bool {
+	return true
+}
+
+func (p SHA512) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
+	return hashStream(sha512.New(), reader, writer)
+}
+
+// Implement DigestProcessor interface
+func (p SHA512) IsDigest() bool {
+	return true
+}
+
 func (p SHA512) Name() string {
 	return "sha512"
 }
@@ -166,11 +251,7 @@ func (p SHA512) Alias() []string {
 }
 
 func (p SHA512) Transform(data []byte, _ ...Flag) (string, error) {
-	h := sha512.New()
-	h.Write(data)
-	bs := h.Sum(nil)
-
-	return fmt.Sprintf("%x", bs), nil
+	return hashHex(sha512.New(), data), nil
 }
 
 func (p SHA512) Flags() []Flag {
@@ -186,6 +267,14 @@ func (p SHA512) Description() string {
 	return "Get the SHA-512 checksum of your text"
 }
 
+func (p SHA512) Category() string {
+	return CategoryHashing
+}
+
+func (p SHA512) Tags() []string {
+	return []string{"checksum", "digest", "sha2"}
+}
+
 func (p SHA512) FilterValue() string {
 	return p.Title()
 }
@@ -202,6 +291,24 @@ func (p SHA224) GetStreamingConfig() StreamingConfig {
 	}
 }
 
+// Implement StreamingProcessor interface
+func (p SHA224) CanStream() bool {
+	return true
+}
+
+func (p SHA224) PreferStream() bool {
+	return true
+}
+
+func (p SHA224) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
+	return hashStream(sha256.New224(), reader, writer)
+}
+
+// Implement DigestProcessor interface
+func (p SHA224) IsDigest() bool {
+	return true
+}
+
 func (p SHA224) Name() string {
 	return "sha224"
 }
@@ -211,9 +318,7 @@ func (p SHA224) Alias() []string {
 }
 
 func (p SHA224) Transform(data []byte, _ ...Flag) (string, error) {
-	bs := sha256.Sum224(data)
-
-	return fmt.Sprintf("%x", bs), nil
+	return hashHex(sha256.New224(), data), nil
 }
 
 func (p SHA224) Flags() []Flag {
@@ -229,6 +334,14 @@ func (p SHA224) Description() string {
 	return "Get the SHA-224 checksum of your text"
 }
 
+func (p SHA224) Category() string {
+	return CategoryHashing
+}
+
+func (p SHA224) Tags() []string {
+	return []string{"checksum", "digest", "sha2"}
+}
+
 func (p SHA224) FilterValue() string {
 	return p.Title()
 }
@@ -245,6 +358,24 @@ func (p SHA384) GetStreamingConfig() StreamingConfig {
 	}
 }
 
+// Implement StreamingProcessor interface
+func (p SHA384) CanStream() bool {
+	return true
+}
+
+func (p SHA384) PreferStream() bool {
+	return true
+}
+
+func (p SHA384) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
+	return hashStream(sha512.New384(), reader, writer)
+}
+
+// Implement DigestProcessor interface
+func (p SHA384) IsDigest() bool {
+	return true
+}
+
 func (p SHA384) Name() string {
 	return "sha384"
 }
@@ -254,9 +385,7 @@ func (p SHA384) Alias() []string {
 }
 
 func (p SHA384) Transform(data []byte, _ ...Flag) (string, error) {
-	bs := sha512.Sum384(data)
-
-	return fmt.Sprintf("%x", bs), nil
+	return hashHex(sha512.New384(), data), nil
 }
 
 func (p SHA384) Flags() []Flag {
@@ -272,6 +401,14 @@ func (p SHA384) Description() string {
 	return "Get the SHA-384 checksum of your text"
 }
 
+func (p SHA384) Category() string {
+	return CategoryHashing
+}
+
+func (p SHA384) Tags() []string {
+	return []string{"checksum", "digest", "sha2"}
+}
+
 func (p SHA384) FilterValue() string {
 	return p.Title()
 }
@@ -288,17 +425,12 @@ func (p Bcrypt) Alias() []string {
 }
 
 func (p Bcrypt) Transform(data []byte, f ...Flag) (string, error) {
-	var rounds uint
-	for _, flag := range f {
-		if flag.Short == "r" {
-			r, ok := flag.Value.(uint)
-			if ok {
-				rounds = r
-			}
-		}
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return "", err
 	}
 
-	bytes, err := bcrypt.GenerateFromPassword(data, int(rounds))
+	bytes, err := bcrypt.GenerateFromPassword(data, int(flags.Uint("number-of-rounds")))
 
 	return string(bytes), err
 }
@@ -311,6 +443,7 @@ func (p Bcrypt) Flags() []Flag {
 			Desc:  "Number of rounds",
 			Value: 10,
 			Type:  FlagUint,
+			Range: &FlagRange{Min: bcrypt.MinCost, Max: bcrypt.MaxCost},
 		},
 	}
 }
@@ -324,6 +457,14 @@ func (p Bcrypt) Description() string {
 	return "Get the bcrypt hash of your text"
 }
 
+func (p Bcrypt) Category() string {
+	return CategoryHashing
+}
+
+func (p Bcrypt) Tags() []string {
+	return []string{"password"}
+}
+
 func (p Bcrypt) FilterValue() string {
 	return p.Title()
 }
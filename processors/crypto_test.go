@@ -536,6 +536,7 @@ func TestBcrypt_Command(t *testing.T) {
 				Desc:  "Number of rounds",
 				Value: 10,
 				Type:  FlagUint,
+				Range: &FlagRange{Min: 4, Max: 31},
 			},
 		},
 		name:  "bcrypt",
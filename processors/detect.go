@@ -0,0 +1,235 @@
+package processors
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Candidate is a way to decode an input found by Detect
+type Candidate struct {
+	// Format is what the input was recognised as, e.g. "base64" or "json in hex"
+	Format string
+	// Chain decodes the input, it ran successfully on it
+	Chain Chain
+	// Part is the part of the input Chain runs on, empty for the whole
+	// input, "payload" for the payload of a JWT
+	Part string
+	// Confidence ranks the candidates, from 0 to 1
+	Confidence float64
+	// Output is the result of Chain
+	Output []byte
+}
+
+// detector recognises a format from the shape of the input and the success
+// of the chain decoding it
+type detector struct {
+	format string
+	chain  string
+	// score is the confidence given by the shape of the input alone,
+	// 0 when the input can't be in the format
+	score func(s string) float64
+	// container formats decode to data which is detected again
+	container bool
+}
+
+var (
+	hexPattern       = regexp.MustCompile(`^([0-9a-fA-F]{2})+$`)
+	base64Pattern    = regexp.MustCompile(`^[A-Za-z0-9+/\r\n]+={0,2}$`)
+	base64URLPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+={0,2}$`)
+	jwtPattern       = regexp.MustCompile(`^eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*$`)
+	percentPattern   = regexp.MustCompile(`%[0-9a-fA-F]{2}`)
+)
+
+// detectors are tried in order, candidates with the same confidence keep it
+var detectors = []detector{
+	{format: "json", chain: "json -i", score: func(s string) float64 {
+		if strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") {
+			return 0.95
+		}
+		return 0
+	}},
+	{format: "base64", chain: "base64-decode", container: true, score: func(s string) float64 {
+		if !base64Pattern.MatchString(s) || len(strings.Join(strings.Fields(s), ""))%4 != 0 {
+			return 0
+		}
+		return base64Score(s, strings.ContainsAny(s, "+/"))
+	}},
+	{format: "base64 (unpadded)", chain: "base64-decode --raw", container: true, score: func(s string) float64 {
+		if !base64Pattern.MatchString(s) || strings.Contains(s, "=") || len(s)%4 == 0 {
+			return 0
+		}
+		return base64Score(s, strings.ContainsAny(s, "+/")) * 0.9
+	}},
+	{format: "base64url", chain: "base64url-decode", container: true, score: func(s string) float64 {
+		// without - or _ it is base64 as well, with the same result
+		if !base64URLPattern.MatchString(s) || !strings.ContainsAny(s, "-_") || len(s)%4 != 0 {
+			return 0
+		}
+		return 0.9
+	}},
+	{format: "base64url (unpadded)", chain: "base64url-decode --raw", container: true, score: func(s string) float64 {
+		if !base64URLPattern.MatchString(s) || !strings.ContainsAny(s, "-_") || strings.Contains(s, "=") || len(s)%4 == 0 {
+			return 0
+		}
+		return 0.8
+	}},
+	{format: "hex", chain: "hex-decode", container: true, score: func(s string) float64 {
+		switch {
+		case !hexPattern.MatchString(s):
+			return 0
+		case strings.Trim(s, "0123456789") == "":
+			// a number is more likely than hex
+			return 0.35
+		}
+		return 0.85
+	}},
+	{format: "url-encoded", chain: "url-decode", score: func(s string) float64 {
+		switch {
+		case percentPattern.MatchString(s):
+			return 0.85
+		case strings.Contains(s, "=") && strings.Contains(s, "+") && !strings.ContainsAny(s, " \n"):
+			return 0.4
+		}
+		return 0
+	}},
+	{format: "msgpack", chain: "msgpack-json", score: func(s string) float64 {
+		if s == "" || utf8.ValidString(s) {
+			return 0
+		}
+		// maps and arrays, the usual top level msgpack values
+		switch b := s[0]; {
+		case b >= 0x80 && b <= 0x9f, b == 0xdc, b == 0xdd, b == 0xde, b == 0xdf:
+			return 0.8
+		}
+		return 0
+	}},
+	{format: "yaml", chain: "yaml-json -i", score: func(s string) float64 {
+		if strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") {
+			// JSON is YAML too, the json candidate is better
+			return 0
+		}
+		if strings.HasPrefix(s, "---") || strings.Contains(s, ": ") || strings.HasPrefix(s, "- ") {
+			return 0.6
+		}
+		return 0
+	}},
+}
+
+// base64Score rates an input made of base64 characters, alphabet tells
+// whether it uses characters which are specific to the encoding
+func base64Score(s string, alphabet bool) float64 {
+	switch {
+	case alphabet || strings.HasSuffix(s, "="):
+		return 0.9
+	case len(s) < 12 && strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) }) < 0:
+		// short words are valid base64 too
+		return 0.3
+	}
+	return 0.75
+}
+
+// Detect classifies data and returns the decoders which succeed on it,
+// ordered by confidence. Decoded data is detected again to find JSON or
+// msgpack encoded with base64 or hex.
+func Detect(data []byte) []Candidate {
+	s := string(bytes.TrimSpace(data))
+	if s == "" {
+		return nil
+	}
+
+	candidates := detect(s, true)
+	if jwtPattern.MatchString(s) {
+		if c, ok := detectJWT(s); ok {
+			candidates = append(candidates, c)
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b Candidate) int {
+		switch {
+		case a.Confidence > b.Confidence:
+			return -1
+		case a.Confidence < b.Confidence:
+			return 1
+		}
+		return 0
+	})
+	return candidates
+}
+
+func detect(s string, nested bool) []Candidate {
+	var candidates []Candidate
+	for _, d := range detectors {
+		score := d.score(s)
+		if score == 0 {
+			continue
+		}
+		chain, err := ParseChain(d.chain)
+		if err != nil {
+			panic(err)
+		}
+		out, err := chain.Transform([]byte(s))
+		if err != nil || out == "" || out == s {
+			continue
+		}
+
+		c := Candidate{Format: d.format, Chain: chain, Confidence: score, Output: []byte(out)}
+		if !isPrintable(c.Output) {
+			// binary results are plausible, but text is a better sign
+			c.Confidence *= 0.7
+		}
+		candidates = append(candidates, c)
+
+		if !nested || !d.container {
+			continue
+		}
+		for _, inner := range detect(strings.TrimSpace(out), false) {
+			if inner.Format != "json" && inner.Format != "msgpack" {
+				continue
+			}
+			// structured data inside is a stronger sign than either format alone
+			candidates = append(candidates, Candidate{
+				Format:     inner.Format + " in " + d.format,
+				Chain:      append(slices.Clone(chain), inner.Chain...),
+				Confidence: min((score+inner.Confidence)/2+0.05, 0.99),
+				Output:     inner.Output,
+			})
+		}
+	}
+	return candidates
+}
+
+// detectJWT decodes the header and the payload of a JSON Web Token, the
+// candidate decodes the payload
+func detectJWT(s string) (Candidate, bool) {
+	chain, err := ParseChain("base64url-decode --raw,json -i")
+	if err != nil {
+		panic(err)
+	}
+	parts := strings.Split(s, ".")
+	header, err := chain.Transform([]byte(parts[0]))
+	if err != nil || !strings.Contains(header, `"alg"`) {
+		return Candidate{}, false
+	}
+	payload, err := chain.Transform([]byte(parts[1]))
+	if err != nil {
+		return Candidate{}, false
+	}
+	return Candidate{Format: "jwt", Chain: chain, Part: "payload", Confidence: 0.98, Output: []byte(payload)}, true
+}
+
+// isPrintable reports whether data is printable UTF-8 text
+func isPrintable(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -24,14 +24,14 @@ func (p ExtractEmails) Transform(data []byte, f ...Flag) (string, error) {
 		emails = append(emails, e.String())
 	}
 
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return "", err
+	}
+	// without the flag emails go on their own lines, as they always did
 	separator := "\n"
-	for _, flag := range f {
-		if flag.Short == "s" {
-			x, ok := flag.Value.(string)
-			if ok {
-				separator = x
-			}
-		}
+	if flags.IsSet("separator") {
+		separator = flags.String("separator")
 	}
 	return strings.Join(emails, separator), nil
 }
@@ -57,6 +57,14 @@ func (p ExtractEmails) Description() string {
 	return "Extract emails from given text"
 }
 
+func (p ExtractEmails) Category() string {
+	return CategoryExtraction
+}
+
+func (p ExtractEmails) Tags() []string {
+	return []string{"find", "grep"}
+}
+
 func (p ExtractEmails) FilterValue() string {
 	return p.Title()
 }
@@ -86,6 +86,11 @@ func TestExtractEmails_Transform(t *testing.T) {
 			args: args{data: []byte("this is example@gmail.com and this is example2@gmail.com"), opts: []Flag{{Short: "s", Value: ","}}},
 			want: "example@gmail.com,example2@gmail.com",
 		},
+		{
+			name: "Multiple Emails with the empty default separator",
+			args: args{data: []byte("this is example@gmail.com and this is example2@gmail.com"), opts: []Flag{{Short: "s", Value: ""}}},
+			want: "example@gmail.comexample2@gmail.com",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,321 @@
+package processors
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FlagRange is an inclusive range of allowed values for Int and Uint flags
+type FlagRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// FlagValues gives typed access to the flags passed to a processor,
+// flags which were not passed get their default Value from Flags()
+type FlagValues struct {
+	defs   []Flag
+	values map[string]any
+	set    map[string]bool
+}
+
+// ParseFlags resolves opts against the flag definitions defs, opts are
+// matched by Name or, when Name is empty, by Short.
+// Every value is converted to the type declared in defs and checked against
+// its Range and Choices, unknown flags and bad values return an error.
+func ParseFlags(defs []Flag, opts ...Flag) (FlagValues, error) {
+	v := FlagValues{
+		defs:   defs,
+		values: make(map[string]any, len(defs)),
+		set:    make(map[string]bool, len(opts)),
+	}
+
+	for _, def := range defs {
+		value, err := convertFlagValue(def, def.Value)
+		if err != nil {
+			return v, fmt.Errorf("flag --%s: invalid default: %w", def.Name, err)
+		}
+		v.values[def.Name] = value
+	}
+
+	for _, opt := range opts {
+		def, ok := findFlag(defs, opt)
+		if !ok {
+			return v, fmt.Errorf("unknown flag %s", flagLabel(opt))
+		}
+		if opt.Value == nil {
+			continue
+		}
+
+		value, err := convertFlagValue(def, opt.Value)
+		if err != nil {
+			return v, fmt.Errorf("flag --%s: %w", def.Name, err)
+		}
+		if err := validateFlagValue(def, value); err != nil {
+			return v, fmt.Errorf("flag --%s: %w", def.Name, err)
+		}
+		v.values[def.Name] = value
+		v.set[def.Name] = true
+	}
+
+	return v, nil
+}
+
+// FlagsFromMap returns the flags of defs set in m, keyed by flag name or
+// shorthand, e.g. the flags given as query parameters or JSON. An empty
+// string sets a Bool flag, like a flag given without a value on the command line.
+func FlagsFromMap(defs []Flag, m map[string]any) ([]Flag, error) {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	flags := make([]Flag, 0, len(m))
+	for _, key := range keys {
+		i := slices.IndexFunc(defs, func(f Flag) bool { return f.Name == key || (f.Short != "" && f.Short == key) })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown flag %s", key)
+		}
+		value := m[key]
+		if defs[i].Type == FlagBool && value == "" {
+			value = true
+		}
+		flags = append(flags, Flag{Name: defs[i].Name, Short: defs[i].Short, Value: value})
+	}
+	if _, err := ParseFlags(defs, flags...); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// ValidateFlags checks opts against the processor Flags() so bad values
+// are reported before Transform runs
+func ValidateFlags(p Processor, opts ...Flag) error {
+	_, err := ParseFlags(p.Flags(), opts...)
+	return err
+}
+
+// ValidateFlagDefs checks the flag definitions of a processor are consistent:
+// Range is only set on Int and Uint flags, Choices only on String flags,
+// and the default Value is one of the allowed values
+func ValidateFlagDefs(defs []Flag) error {
+	for _, def := range defs {
+		if def.Range != nil {
+			if def.Type != FlagInt && def.Type != FlagUint {
+				return fmt.Errorf("flag --%s: range is only supported on int and uint flags", def.Name)
+			}
+			if def.Range.Min > def.Range.Max {
+				return fmt.Errorf("flag --%s: range min %d is greater than max %d", def.Name, def.Range.Min, def.Range.Max)
+			}
+		}
+		if len(def.Choices) > 0 && def.Type != FlagString {
+			return fmt.Errorf("flag --%s: choices are only supported on string flags", def.Name)
+		}
+
+		value, err := convertFlagValue(def, def.Value)
+		if err != nil {
+			return fmt.Errorf("flag --%s: invalid default: %w", def.Name, err)
+		}
+		if err := validateFlagValue(def, value); err != nil {
+			return fmt.Errorf("flag --%s: invalid default: %w", def.Name, err)
+		}
+	}
+	return nil
+}
+
+// IsSet reports whether the flag was explicitly passed
+func (v FlagValues) IsSet(name string) bool {
+	return v.set[name]
+}
+
+// Bool returns the value of a Bool flag
+func (v FlagValues) Bool(name string) bool {
+	b, _ := v.values[name].(bool)
+	return b
+}
+
+// Int returns the value of an Int flag
+func (v FlagValues) Int(name string) int {
+	i, _ := v.values[name].(int)
+	return i
+}
+
+// Uint returns the value of a Uint flag
+func (v FlagValues) Uint(name string) uint {
+	u, _ := v.values[name].(uint)
+	return u
+}
+
+// String returns the value of a String flag
+func (v FlagValues) String(name string) string {
+	s, _ := v.values[name].(string)
+	return s
+}
+
+// Map returns the value of every flag by name, typed like the getters
+func (v FlagValues) Map() map[string]any {
+	m := make(map[string]any, len(v.defs))
+	for _, def := range v.defs {
+		switch def.Type {
+		case FlagBool:
+			m[def.Name] = v.Bool(def.Name)
+		case FlagInt:
+			m[def.Name] = v.Int(def.Name)
+		case FlagUint:
+			m[def.Name] = v.Uint(def.Name)
+		default:
+			m[def.Name] = v.String(def.Name)
+		}
+	}
+	return m
+}
+
+// findFlag returns the definition matching opt
+func findFlag(defs []Flag, opt Flag) (Flag, bool) {
+	for _, def := range defs {
+		if opt.Name != "" && opt.Name == def.Name {
+			return def, true
+		}
+		if opt.Name == "" && opt.Short != "" && opt.Short == def.Short {
+			return def, true
+		}
+	}
+	return Flag{}, false
+}
+
+func flagLabel(f Flag) string {
+	if f.Name != "" {
+		return "--" + f.Name
+	}
+	return "-" + f.Short
+}
+
+// convertFlagValue converts value to the Go type matching def.Type,
+// strings are parsed so values coming from text (config files, query params) work
+func convertFlagValue(def Flag, value any) (any, error) {
+	switch def.Type {
+	case FlagBool:
+		switch x := value.(type) {
+		case nil:
+			return false, nil
+		case bool:
+			return x, nil
+		case string:
+			b, err := strconv.ParseBool(x)
+			if err != nil {
+				return nil, fmt.Errorf("invalid boolean %q", x)
+			}
+			return b, nil
+		}
+	case FlagInt:
+		if value == nil {
+			return 0, nil
+		}
+		i, err := toInt64(value)
+		if err != nil {
+			return nil, err
+		}
+		if i < math.MinInt || i > math.MaxInt {
+			return nil, fmt.Errorf("%d is out of range", i)
+		}
+		return int(i), nil
+	case FlagUint:
+		if value == nil {
+			return uint(0), nil
+		}
+		if u, ok := value.(uint); ok {
+			return u, nil
+		}
+		i, err := toInt64(value)
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("%d must not be negative", i)
+		}
+		return uint(i), nil
+	case FlagString:
+		switch x := value.(type) {
+		case nil:
+			return "", nil
+		case string:
+			return x, nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported flag type %q", def.Type)
+	}
+
+	return nil, fmt.Errorf("expected %s value, got %T", strings.ToLower(def.Type.String()), value)
+}
+
+func toInt64(value any) (int64, error) {
+	switch x := value.(type) {
+	case int:
+		return int64(x), nil
+	case int8:
+		return int64(x), nil
+	case int16:
+		return int64(x), nil
+	case int32:
+		return int64(x), nil
+	case int64:
+		return x, nil
+	case uint:
+		if uint64(x) > math.MaxInt64 {
+			return 0, fmt.Errorf("%d is out of range", x)
+		}
+		return int64(x), nil
+	case uint8:
+		return int64(x), nil
+	case uint16:
+		return int64(x), nil
+	case uint32:
+		return int64(x), nil
+	case uint64:
+		if x > math.MaxInt64 {
+			return 0, fmt.Errorf("%d is out of range", x)
+		}
+		return int64(x), nil
+	case float64:
+		if x != math.Trunc(x) {
+			return 0, fmt.Errorf("%v is not a whole number", x)
+		}
+		return int64(x), nil
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(x), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", x)
+		}
+		return i, nil
+	}
+	return 0, fmt.Errorf("expected a number, got %T", value)
+}
+
+// validateFlagValue checks value against the Range and Choices of def
+func validateFlagValue(def Flag, value any) error {
+	if def.Range != nil {
+		var n int64
+		switch x := value.(type) {
+		case int:
+			n = int64(x)
+		case uint:
+			n = int64(x)
+		}
+		if n < int64(def.Range.Min) || n > int64(def.Range.Max) {
+			return fmt.Errorf("%d is out of range, must be between %d and %d", n, def.Range.Min, def.Range.Max)
+		}
+	}
+
+	if len(def.Choices) > 0 {
+		s := fmt.Sprint(value)
+		if !slices.Contains(def.Choices, s) {
+			return fmt.Errorf("invalid value %q, must be one of: %s", s, strings.Join(def.Choices, ", "))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package processors
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	defs := []Flag{
+		{Name: "count", Short: "c", Value: uint(3), Type: FlagUint, Range: &FlagRange{Min: 1, Max: 10}},
+		{Name: "offset", Short: "o", Value: 0, Type: FlagInt},
+		{Name: "mode", Short: "m", Value: "fast", Type: FlagString, Choices: []string{"fast", "slow"}},
+		{Name: "verbose", Short: "v", Type: FlagBool},
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Flag
+		check   func(t *testing.T, v FlagValues)
+		wantErr string
+	}{
+		{
+			name: "Defaults",
+			check: func(t *testing.T, v FlagValues) {
+				if v.Uint("count") != 3 || v.Int("offset") != 0 || v.String("mode") != "fast" || v.Bool("verbose") {
+					t.Errorf("unexpected defaults: %+v", v.values)
+				}
+				if v.IsSet("count") {
+					t.Errorf("IsSet(count) = true, want false")
+				}
+				want := map[string]any{"count": uint(3), "offset": 0, "mode": "fast", "verbose": false}
+				if !reflect.DeepEqual(v.Map(), want) {
+					t.Errorf("Map() = %v, want %v", v.Map(), want)
+				}
+			},
+		},
+		{
+			name: "Match by short and name",
+			opts: []Flag{{Short: "c", Value: uint(7)}, {Name: "mode", Value: "slow"}, {Short: "v", Value: true}},
+			check: func(t *testing.T, v FlagValues) {
+				if v.Uint("count") != 7 || v.String("mode") != "slow" || !v.Bool("verbose") {
+					t.Errorf("unexpected values: %+v", v.values)
+				}
+				if !v.IsSet("count") {
+					t.Errorf("IsSet(count) = false, want true")
+				}
+			},
+		},
+		{
+			name: "Convert from other types",
+			opts: []Flag{{Short: "c", Value: 4}, {Short: "o", Value: "-2"}, {Short: "v", Value: "true"}},
+			check: func(t *testing.T, v FlagValues) {
+				if v.Uint("count") != 4 || v.Int("offset") != -2 || !v.Bool("verbose") {
+					t.Errorf("unexpected values: %+v", v.values)
+				}
+			},
+		},
+		{
+			name: "Convert whole float",
+			opts: []Flag{{Short: "c", Value: float64(2)}},
+			check: func(t *testing.T, v FlagValues) {
+				if v.Uint("count") != 2 {
+					t.Errorf("Uint(count) = %d, want 2", v.Uint("count"))
+				}
+			},
+		},
+		{
+			name: "Nil value keeps default",
+			opts: []Flag{{Short: "c"}},
+			check: func(t *testing.T, v FlagValues) {
+				if v.Uint("count") != 3 {
+					t.Errorf("Uint(count) = %d, want 3", v.Uint("count"))
+				}
+			},
+		},
+		{name: "Unknown flag", opts: []Flag{{Short: "x", Value: 1}}, wantErr: "unknown flag -x"},
+		{name: "Unknown long flag", opts: []Flag{{Name: "nope", Value: 1}}, wantErr: "unknown flag --nope"},
+		{name: "Out of range", opts: []Flag{{Short: "c", Value: uint(11)}}, wantErr: "must be between 1 and 10"},
+		{name: "Negative uint", opts: []Flag{{Short: "c", Value: -1}}, wantErr: "must not be negative"},
+		{name: "Invalid choice", opts: []Flag{{Short: "m", Value: "medium"}}, wantErr: "must be one of: fast, slow"},
+		{name: "Invalid number", opts: []Flag{{Short: "o", Value: "abc"}}, wantErr: `invalid number "abc"`},
+		{name: "Fractional number", opts: []Flag{{Short: "o", Value: 1.5}}, wantErr: "not a whole number"},
+		{name: "Wrong type", opts: []Flag{{Short: "m", Value: 1}}, wantErr: "expected string value"},
+		{name: "Invalid boolean", opts: []Flag{{Short: "v", Value: "maybe"}}, wantErr: `invalid boolean "maybe"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := ParseFlags(defs, tt.opts...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseFlags() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+			tt.check(t, v)
+		})
+	}
+}
+
+func TestValidateFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       Processor
+		opts    []Flag
+		wantErr bool
+	}{
+		{name: "CRC32 valid polynomial", p: CRC32{}, opts: []Flag{{Short: "p", Value: "koopman"}}},
+		{name: "CRC32 invalid polynomial", p: CRC32{}, opts: []Flag{{Short: "p", Value: "crc64"}}, wantErr: true},
+		{name: "BLAKE2b size too big", p: BLAKE2b{}, opts: []Flag{{Short: "s", Value: uint(65)}}, wantErr: true},
+		{name: "Bcrypt cost too low", p: Bcrypt{}, opts: []Flag{{Short: "r", Value: uint(2)}}, wantErr: true},
+		{name: "Morse invalid lang", p: MorseCodeDecode{}, opts: []Flag{{Short: "l", Value: "xx"}}, wantErr: true},
+		{name: "Processor without flags", p: Upper{}, opts: []Flag{{Short: "x", Value: true}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateFlags(tt.p, tt.opts...); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFlagsFromMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       Processor
+		m       map[string]any
+		want    map[string]any
+		wantErr string
+	}{
+		{name: "Names and shorthands", p: Zeropad{}, m: map[string]any{"n": "3", "prefix": "x"}, want: map[string]any{"number-of-zeros": uint(3), "prefix": "x"}},
+		{name: "JSON numbers", p: Zeropad{}, m: map[string]any{"number-of-zeros": float64(2)}, want: map[string]any{"number-of-zeros": uint(2), "prefix": ""}},
+		{name: "Bool flag without a value", p: FormatJSON{}, m: map[string]any{"indent": ""}, want: map[string]any{"indent": true}},
+		{name: "Unknown flag", p: FormatJSON{}, m: map[string]any{"nope": "1"}, wantErr: "unknown flag nope"},
+		{name: "Invalid value", p: CRC32{}, m: map[string]any{"p": "crc64"}, wantErr: "invalid value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, err := FlagsFromMap(tt.p.Flags(), tt.m)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("FlagsFromMap() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FlagsFromMap() error = %v", err)
+			}
+			values, err := ParseFlags(tt.p.Flags(), flags...)
+			if err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+			if got := values.Map(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FlagsFromMap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateFlagDefs(t *testing.T) {
+	tests := []struct {
+		name    string
+		defs    []Flag
+		wantErr bool
+	}{
+		{name: "No flags"},
+		{name: "Valid choices", defs: []Flag{{Name: "mode", Type: FlagString, Value: "a", Choices: []string{"a", "b"}}}},
+		{name: "Valid range", defs: []Flag{{Name: "n", Type: FlagUint, Value: uint(2), Range: &FlagRange{Min: 1, Max: 3}}}},
+		{name: "Default not a choice", defs: []Flag{{Name: "mode", Type: FlagString, Value: "c", Choices: []string{"a", "b"}}}, wantErr: true},
+		{name: "Choices on bool", defs: []Flag{{Name: "b", Type: FlagBool, Choices: []string{"true"}}}, wantErr: true},
+		{name: "Range on string", defs: []Flag{{Name: "s", Type: FlagString, Range: &FlagRange{Max: 1}}}, wantErr: true},
+		{name: "Inverted range", defs: []Flag{{Name: "n", Type: FlagInt, Range: &FlagRange{Min: 3, Max: 1}}}, wantErr: true},
+		{name: "Default out of range", defs: []Flag{{Name: "n", Type: FlagInt, Value: 5, Range: &FlagRange{Min: 1, Max: 3}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateFlagDefs(tt.defs); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFlagDefs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	for _, p := range All() {
+		if err := ValidateFlagDefs(p.Flags()); err != nil {
+			t.Errorf("%s: %v", p.Name(), err)
+		}
+	}
+}
@@ -42,6 +42,14 @@ func (p HexEncode) Description() string {
 	return "Encode your text Hex"
 }
 
+func (p HexEncode) Category() string {
+	return CategoryEncoding
+}
+
+func (p HexEncode) Tags() []string {
+	return []string{"base16"}
+}
+
 func (p HexEncode) FilterValue() string {
 	return p.Title()
 }
@@ -49,11 +57,11 @@ func (p HexEncode) FilterValue() string {
 // HexDecode decodes hexadecimal to string
 type HexDecode struct{}
 
-// Implement ConfigurableStreamingProcessor interface for buffered processing
+// Implement ConfigurableStreamingProcessor interface for chunked processing
 func (p HexDecode) GetStreamingConfig() StreamingConfig {
 	return StreamingConfig{
 		ChunkSize:    64 * 1024, // 64KB chunks
-		BufferOutput: true,      // Hex decoding needs complete input
+		BufferOutput: true,      // Hex decoding needs complete input, invalid input must not leave partial output
 		LineByLine:   false,
 	}
 }
@@ -66,13 +74,19 @@ func (p HexDecode) Alias() []string {
 	return []string{"hex-dec", "hexadecimal-decode"}
 }
 
-func (p HexDecode) Transform(data []byte, _ ...Flag) (string, error) {
+func (p HexDecode) Transform(data []byte, f ...Flag) (string, error) {
+	out, err := p.TransformBytes(data, f...)
+	return string(out), err
+}
+
+// Implement BinaryProcessor interface, the output is arbitrary bytes
+func (p HexDecode) TransformBytes(data []byte, _ ...Flag) ([]byte, error) {
 	output, err := hex.DecodeString(string(data))
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(output), nil
+	return output, nil
 }
 
 func (p HexDecode) Flags() []Flag {
@@ -88,6 +102,14 @@ func (p HexDecode) Description() string {
 	return "Convert Hexadecimal to String"
 }
 
+func (p HexDecode) Category() string {
+	return CategoryEncoding
+}
+
+func (p HexDecode) Tags() []string {
+	return []string{"base16"}
+}
+
 func (p HexDecode) FilterValue() string {
 	return p.Title()
 }
@@ -33,6 +33,14 @@ func (p HTMLEncode) Description() string {
 	return "Escape your HTML"
 }
 
+func (p HTMLEncode) Category() string {
+	return CategoryEncoding
+}
+
+func (p HTMLEncode) Tags() []string {
+	return []string{"entities", "escape"}
+}
+
 func (p HTMLEncode) FilterValue() string {
 	return p.Title()
 }
@@ -65,6 +73,14 @@ func (p HTMLDecode) Description() string {
 	return "Unescape your HTML"
 }
 
+func (p HTMLDecode) Category() string {
+	return CategoryEncoding
+}
+
+func (p HTMLDecode) Tags() []string {
+	return []string{"entities", "unescape"}
+}
+
 func (p HTMLDecode) FilterValue() string {
 	return p.Title()
 }
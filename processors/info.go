@@ -0,0 +1,68 @@
+package processors
+
+import (
+	"slices"
+	"strings"
+)
+
+// Info describes a processor for the tools building their own menus on
+// top of sttr: sttr list --json, the HTTP server and the editor integrations
+type Info struct {
+	Name         string        `json:"name"`
+	Aliases      []string      `json:"aliases"`
+	Title        string        `json:"title"`
+	Description  string        `json:"description"`
+	Category     string        `json:"category"`
+	Tags         []string      `json:"tags"`
+	Streaming    StreamingMode `json:"streaming"`
+	PreferStream bool          `json:"prefer_stream"`
+	Binary       bool          `json:"binary"`
+	Digest       bool          `json:"digest"`
+	Flags        []FlagInfo    `json:"flags"`
+}
+
+// FlagInfo describes a flag of a processor, Type is the lowercase FlagType
+type FlagInfo struct {
+	Name        string     `json:"name"`
+	Short       string     `json:"short,omitempty"`
+	Type        string     `json:"type"`
+	Description string     `json:"description"`
+	Default     any        `json:"default,omitempty"`
+	Choices     []string   `json:"choices,omitempty"`
+	Range       *FlagRange `json:"range,omitempty"`
+}
+
+// InfoOf collects the metadata of p, lists are never nil
+func InfoOf(p Processor) Info {
+	info := Info{
+		Name:         p.Name(),
+		Aliases:      slices.DeleteFunc(slices.Clone(p.Alias()), func(a string) bool { return a == p.Name() }),
+		Title:        TitleOf(p),
+		Description:  DescriptionOf(p),
+		Category:     CategoryOf(p),
+		Tags:         slices.Clone(TagsOf(p)),
+		Streaming:    StreamingModeOf(p),
+		PreferStream: PreferStream(p),
+		Binary:       IsBinary(p),
+		Digest:       IsDigest(p),
+		Flags:        []FlagInfo{},
+	}
+	if info.Aliases == nil {
+		info.Aliases = []string{}
+	}
+	if info.Tags == nil {
+		info.Tags = []string{}
+	}
+	for _, f := range p.Flags() {
+		info.Flags = append(info.Flags, FlagInfo{
+			Name:        f.Name,
+			Short:       f.Short,
+			Type:        strings.ToLower(f.Type.String()),
+			Description: f.Desc,
+			Default:     f.Value,
+			Choices:     f.Choices,
+			Range:       f.Range,
+		})
+	}
+	return info
+}
@@ -0,0 +1,28 @@
+package processors
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestInfoOf(t *testing.T) {
+	info := InfoOf(CRC32{})
+	if info.Name != "crc32" || info.Category != CategoryHashing || !info.Digest || info.Streaming != StreamNative {
+		t.Errorf("InfoOf() = %+v", info)
+	}
+	if len(info.Flags) != 1 || info.Flags[0].Type != "string" || len(info.Flags[0].Choices) == 0 {
+		t.Errorf("InfoOf().Flags = %+v", info.Flags)
+	}
+
+	// lists are empty rather than null for the tools reading the JSON
+	data, err := json.Marshal(InfoOf(registryTestProcessor{name: "plain-name"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"aliases":[]`, `"tags":[]`, `"flags":[]`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("json.Marshal(InfoOf()) = %s, want %s", data, want)
+		}
+	}
+}
@@ -58,3 +58,11 @@ func (p ExtractIPs) Title() string {
 func (p ExtractIPs) Description() string {
 	return "Extract IPv4 and IPv6 from your text"
 }
+
+func (p ExtractIPs) Category() string {
+	return CategoryExtraction
+}
+
+func (p ExtractIPs) Tags() []string {
+	return []string{"find", "grep", "ipv4", "ipv6"}
+}
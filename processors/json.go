@@ -41,20 +41,16 @@ func unmarshalJSON(data []byte) (any, error) {
 }
 
 func (p FormatJSON) Transform(data []byte, f ...Flag) (string, error) {
-	objmap, err := unmarshalJSON(data)
+	flags, err := ParseFlags(p.Flags(), f...)
 	if err != nil {
 		return "", err
 	}
-	var indent bool
-	for _, flag := range f {
-		if flag.Short == "i" {
-			if b, ok := flag.Value.(bool); ok {
-				indent = b
-			}
-		}
+	objmap, err := unmarshalJSON(data)
+	if err != nil {
+		return "", err
 	}
 	var newJSON []byte
-	if indent {
+	if flags.Bool("indent") {
 		newJSON, err = json.MarshalIndent(objmap, "", "  ")
 	} else {
 		newJSON, err = json.Marshal(objmap)
@@ -78,6 +74,14 @@ func (p FormatJSON) Description() string {
 	return "Format your text as JSON ( json decode )"
 }
 
+func (p FormatJSON) Category() string {
+	return CategoryDataFormats
+}
+
+func (p FormatJSON) Tags() []string {
+	return []string{"format", "pretty", "indent", "minify"}
+}
+
 func (p FormatJSON) FilterValue() string {
 	return p.Title()
 }
@@ -114,6 +118,14 @@ func (p JSONToYAML) Description() string {
 	return "Convert JSON to YAML text"
 }
 
+func (p JSONToYAML) Category() string {
+	return CategoryDataFormats
+}
+
+func (p JSONToYAML) Tags() []string {
+	return []string{"convert"}
+}
+
 func (p JSONToYAML) FilterValue() string {
 	return p.Title()
 }
@@ -129,20 +141,26 @@ func (p JSONToMSGPACK) Alias() []string {
 	return []string{}
 }
 
-func (p JSONToMSGPACK) Transform(data []byte, _ ...Flag) (string, error) {
+func (p JSONToMSGPACK) Transform(data []byte, f ...Flag) (string, error) {
+	out, err := p.TransformBytes(data, f...)
+	return string(out), err
+}
+
+// Implement BinaryProcessor interface, the output is arbitrary bytes
+func (p JSONToMSGPACK) TransformBytes(data []byte, _ ...Flag) ([]byte, error) {
 	var rawData any
 
 	err := json.Unmarshal(data, &rawData)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	m, err := msgpack.Marshal(rawData)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(m), nil
+	return m, nil
 }
 
 func (p JSONToMSGPACK) Flags() []Flag {
@@ -158,6 +176,14 @@ func (p JSONToMSGPACK) Description() string {
 	return "Convert JSON to MSGPACK text"
 }
 
+func (p JSONToMSGPACK) Category() string {
+	return CategoryDataFormats
+}
+
+func (p JSONToMSGPACK) Tags() []string {
+	return []string{"convert", "binary"}
+}
+
 func (p JSONToMSGPACK) FilterValue() string {
 	return p.Title()
 }
@@ -202,6 +228,14 @@ func (p MSGPACKToJSON) Description() string {
 	return "Convert MSGPACK to JSON text"
 }
 
+func (p MSGPACKToJSON) Category() string {
+	return CategoryDataFormats
+}
+
+func (p MSGPACKToJSON) Tags() []string {
+	return []string{"convert", "binary"}
+}
+
 func (p MSGPACKToJSON) FilterValue() string {
 	return p.Title()
 }
@@ -241,6 +275,14 @@ func (p YAMLToJSON) Description() string {
 	return "Convert YAML to JSON text"
 }
 
+func (p YAMLToJSON) Category() string {
+	return CategoryDataFormats
+}
+
+func (p YAMLToJSON) Tags() []string {
+	return []string{"convert"}
+}
+
 func (p YAMLToJSON) FilterValue() string {
 	return p.Title()
 }
@@ -266,16 +308,12 @@ func (p JSONUnescape) Transform(data []byte, f ...Flag) (string, error) {
 		return "", err
 	}
 
-	var indent bool
-	for _, flag := range f {
-		if flag.Short == "i" {
-			if b, ok := flag.Value.(bool); ok {
-				indent = b
-			}
-		}
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return "", err
 	}
 	var newJSON []byte
-	if indent {
+	if flags.Bool("indent") {
 		newJSON, err = json.MarshalIndent(objmap, "", "  ")
 	} else {
 		newJSON, err = json.Marshal(objmap)
@@ -299,6 +337,14 @@ func (p JSONUnescape) Description() string {
 	return "JSON Unescape"
 }
 
+func (p JSONUnescape) Category() string {
+	return CategoryEncoding
+}
+
+func (p JSONUnescape) Tags() []string {
+	return []string{"string"}
+}
+
 func (p JSONUnescape) FilterValue() string {
 	return p.Title()
 }
@@ -345,6 +391,14 @@ func (p JSONEscape) Description() string {
 	return "JSON Escape"
 }
 
+func (p JSONEscape) Category() string {
+	return CategoryEncoding
+}
+
+func (p JSONEscape) Tags() []string {
+	return []string{"string", "quote"}
+}
+
 func (p JSONEscape) FilterValue() string {
 	return p.Title()
 }
@@ -60,6 +60,10 @@ func (p CountLines) Description() string {
 	return "Count the number of lines in your text"
 }
 
+func (p CountLines) Category() string {
+	return CategoryLines
+}
+
 func (p CountLines) FilterValue() string {
 	return p.Title()
 }
@@ -108,6 +112,14 @@ func (p SortLines) Description() string {
 	return "Sort lines alphabetically"
 }
 
+func (p SortLines) Category() string {
+	return CategoryLines
+}
+
+func (p SortLines) Tags() []string {
+	return []string{"order"}
+}
+
 func (p SortLines) FilterValue() string {
 	return p.Title()
 }
@@ -150,6 +162,14 @@ func (p ShuffleLines) Description() string {
 	return "Shuffle lines randomly"
 }
 
+func (p ShuffleLines) Category() string {
+	return CategoryLines
+}
+
+func (p ShuffleLines) Tags() []string {
+	return []string{"random"}
+}
+
 func (p ShuffleLines) FilterValue() string {
 	return p.Title()
 }
@@ -221,6 +241,14 @@ func (p UniqueLines) Description() string {
 	return "Unique Lines"
 }
 
+func (p UniqueLines) Category() string {
+	return CategoryLines
+}
+
+func (p UniqueLines) Tags() []string {
+	return []string{"dedupe", "distinct"}
+}
+
 func (p UniqueLines) FilterValue() string {
 	return p.Title()
 }
@@ -270,6 +298,10 @@ func (p ReverseLines) Description() string {
 	return "Reverse Lines"
 }
 
+func (p ReverseLines) Category() string {
+	return CategoryLines
+}
+
 func (p ReverseLines) FilterValue() string {
 	return p.Title()
 }
@@ -39,6 +39,14 @@ func (p Markdown) Description() string {
 	return "Convert Markdown to HTML"
 }
 
+func (p Markdown) Category() string {
+	return CategoryDataFormats
+}
+
+func (p Markdown) Tags() []string {
+	return []string{"convert", "render"}
+}
+
 func (p Markdown) FilterValue() string {
 	return p.Title()
 }
@@ -3,7 +3,6 @@ package processors
 
 import (
 	"fmt"
-	"slices"
 	"strings"
 )
 
@@ -125,26 +124,12 @@ var morseLangs = []string{
 	"la", "ru", "gr", "he", "ar", "ja", "kr", "th",
 }
 var morseDecodeLangFlag = Flag{
-	Name:  "lang",
-	Short: "l",
-	Desc:  "Morse code set to decode [la(Latin), ru(Cyrillic), gr(Greek), he(Hebrew), ar(Arabic), ja(Japanese), kr(Korean), th(Thai)]",
-	Value: "la",
-	Type:  FlagString,
-}
-
-func checkDecodeLangFlag(f []Flag) string {
-	lang := "la"
-	for _, flag := range f {
-		if flag.Short == "l" {
-			l, ok := flag.Value.(string)
-			if ok {
-				if slices.Contains(morseLangs, l) {
-					lang = l
-				}
-			}
-		}
-	}
-	return lang
+	Name:    "lang",
+	Short:   "l",
+	Desc:    "Morse code set to decode [la(Latin), ru(Cyrillic), gr(Greek), he(Hebrew), ar(Arabic), ja(Japanese), kr(Korean), th(Thai)]",
+	Value:   "la",
+	Type:    FlagString,
+	Choices: morseLangs,
 }
 
 // MorseCodeEncode encodes string to Morse Code.
@@ -199,6 +184,10 @@ func (p MorseCodeEncode) Description() string {
 	return "Encode your text to Morse Code"
 }
 
+func (p MorseCodeEncode) Category() string {
+	return CategoryEncoding
+}
+
 func (p MorseCodeEncode) FilterValue() string {
 	return p.Title()
 }
@@ -218,7 +207,11 @@ func (p MorseCodeDecode) Transform(data []byte, f ...Flag) (string, error) {
 	res := ""
 	wordSeparator := "/"
 	letterSeparator := " "
-	codeLang := checkDecodeLangFlag(f)
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return "", err
+	}
+	codeLang := flags.String("lang")
 	for part := range strings.SplitSeq(string(data), letterSeparator) {
 		found := false
 		for key, val := range morseCodeMap[codeLang] {
@@ -253,6 +246,10 @@ func (p MorseCodeDecode) Description() string {
 	return "Decode Morse Code to text"
 }
 
+func (p MorseCodeDecode) Category() string {
+	return CategoryEncoding
+}
+
 func (p MorseCodeDecode) FilterValue() string {
 	return p.Title()
 }
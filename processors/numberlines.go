@@ -65,6 +65,10 @@ func (p NumberLines) Description() string {
 	return "Prepends consecutive number to each input line"
 }
 
+func (p NumberLines) Category() string {
+	return CategoryLines
+}
+
 func (p NumberLines) FilterValue() string {
 	return p.Title()
 }
@@ -6,6 +6,7 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/list"
 	"golang.org/x/text/cases"
@@ -102,6 +103,40 @@ type Processor interface {
 	Flags() []Flag
 }
 
+// DigestProcessor is an optional interface for hash and checksum processors,
+// their output is a fixed size digest of the input
+type DigestProcessor interface {
+	IsDigest() bool
+}
+
+// IsDigest reports whether the output of p is a digest of its input
+func IsDigest(p Processor) bool {
+	d, ok := p.(DigestProcessor)
+	return ok && d.IsDigest()
+}
+
+// BinaryProcessor is an optional interface for processors whose output is
+// arbitrary bytes rather than text, such as decoders and binary formats.
+// TransformBytes returns the same output as Transform without going through a string.
+type BinaryProcessor interface {
+	TransformBytes(data []byte, opts ...Flag) ([]byte, error)
+}
+
+// IsBinary reports whether the output of p may not be text
+func IsBinary(p Processor) bool {
+	_, ok := p.(BinaryProcessor)
+	return ok
+}
+
+// TransformBytes runs p on data, through TransformBytes when p implements BinaryProcessor
+func TransformBytes(p Processor, data []byte, opts ...Flag) ([]byte, error) {
+	if bp, ok := p.(BinaryProcessor); ok {
+		return bp.TransformBytes(data, opts...)
+	}
+	out, err := p.Transform(data, opts...)
+	return []byte(out), err
+}
+
 // StreamingConfig defines how a processor should handle streaming
 type StreamingConfig struct {
 	// ChunkSize defines the size of chunks to read from input (default: 64KB)
@@ -110,6 +145,11 @@ type StreamingConfig struct {
 	BufferOutput bool
 	// LineByLine whether to process input line by line (useful for line-based processors)
 	LineByLine bool
+	// BlockSize makes every chunk passed to Transform a multiple of BlockSize bytes,
+	// only the last chunk can be shorter (e.g. 3 for base64 encoding)
+	BlockSize int
+	// RuneAligned never splits a multi-byte UTF-8 character across two chunks
+	RuneAligned bool
 }
 
 // ConfigurableStreamingProcessor is an optional interface that processors can implement
@@ -151,6 +191,13 @@ type Flag struct {
 
 	// Value - optional default value of the flag
 	Value any
+
+	// Range - optional inclusive bounds for Int and Uint flags
+	Range *FlagRange
+
+	// Choices - optional list of allowed values for String flags,
+	// used for validation, shell completion and the interactive picker
+	Choices []string
 }
 
 // DefaultStreamingConfig provides sensible defaults for streaming
@@ -161,23 +208,64 @@ var DefaultStreamingConfig = StreamingConfig{
 }
 
 // TransformStream provides a central streaming function that works with any processor
-// It uses the processor's existing Transform method to handle streaming data
+// A processor's own StreamingProcessor implementation always wins, otherwise
+// the processor's existing Transform method is used to handle streaming data
 func TransformStream(processor Processor, reader io.Reader, writer io.Writer, opts ...Flag) error {
-	// Get streaming configuration
-	config := DefaultStreamingConfig
-	if sp, ok := processor.(ConfigurableStreamingProcessor); ok {
-		config = sp.GetStreamingConfig()
+	if sp, ok := processor.(StreamingProcessor); ok && sp.CanStream() {
+		return sp.TransformStream(reader, writer, opts...)
 	}
 
-	if config.LineByLine {
+	config := streamingConfig(processor)
+	switch StreamingModeOf(processor) {
+	case StreamLines:
 		return transformStreamLineByLine(processor, reader, writer, opts...)
+	case StreamBuffered:
+		return transformStreamBuffered(processor, reader, writer, opts...)
 	}
 
-	if config.BufferOutput {
-		return transformStreamBuffered(processor, reader, writer, opts...)
+	return transformStreamChunked(processor, reader, writer, config, opts...)
+}
+
+// StreamingMode is how TransformStream processes the input of a processor
+type StreamingMode string
+
+const (
+	// StreamNative is the processor's own StreamingProcessor implementation
+	StreamNative StreamingMode = "native"
+	// StreamLines calls Transform once per line
+	StreamLines StreamingMode = "lines"
+	// StreamChunked calls Transform once per chunk of input
+	StreamChunked StreamingMode = "chunked"
+	// StreamBuffered reads the whole input and calls Transform once
+	StreamBuffered StreamingMode = "buffered"
+)
+
+// StreamingModeOf returns the way TransformStream handles processor
+func StreamingModeOf(processor Processor) StreamingMode {
+	if sp, ok := processor.(StreamingProcessor); ok && sp.CanStream() {
+		return StreamNative
 	}
 
-	return transformStreamChunked(processor, reader, writer, config.ChunkSize, opts...)
+	config := streamingConfig(processor)
+	switch {
+	case config.LineByLine:
+		return StreamLines
+	case config.BufferOutput:
+		return StreamBuffered
+	}
+	return StreamChunked
+}
+
+// streamingConfig returns the streaming configuration of processor.
+// Processors without one are buffered: most of them need the whole input,
+// e.g. json or reverse, so only a configuration can make them chunked.
+func streamingConfig(processor Processor) StreamingConfig {
+	if sp, ok := processor.(ConfigurableStreamingProcessor); ok {
+		return sp.GetStreamingConfig()
+	}
+	config := DefaultStreamingConfig
+	config.BufferOutput = true
+	return config
 }
 
 // transformStreamLineByLine processes input line by line
@@ -213,18 +301,25 @@ func transformStreamBuffered(processor Processor, reader io.Reader, writer io.Wr
 }
 
 // transformStreamChunked processes input in chunks (for processors that can handle partial data)
-func transformStreamChunked(processor Processor, reader io.Reader, writer io.Writer, chunkSize int, opts ...Flag) error {
+// Chunks are aligned to the processor's BlockSize and rune boundaries so the
+// output is byte identical to transforming the whole input at once
+func transformStreamChunked(processor Processor, reader io.Reader, writer io.Writer, config StreamingConfig, opts ...Flag) error {
+	chunkSize := config.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultStreamingConfig.ChunkSize
+	}
 	buffer := make([]byte, chunkSize)
+	var pending []byte
 
 	for {
 		n, err := reader.Read(buffer)
 		if n > 0 {
-			result, transformErr := processor.Transform(buffer[:n], opts...)
-			if transformErr != nil {
-				return transformErr
-			}
-			if _, writeErr := writer.Write([]byte(result)); writeErr != nil {
-				return writeErr
+			pending = append(pending, buffer[:n]...)
+			if cut := alignedLength(pending, config); cut > 0 {
+				if transformErr := transformChunk(processor, pending[:cut], writer, opts...); transformErr != nil {
+					return transformErr
+				}
+				pending = append(pending[:0], pending[cut:]...)
 			}
 		}
 
@@ -236,9 +331,43 @@ func transformStreamChunked(processor Processor, reader io.Reader, writer io.Wri
 		}
 	}
 
+	if len(pending) > 0 {
+		return transformChunk(processor, pending, writer, opts...)
+	}
 	return nil
 }
 
+// alignedLength returns how many leading bytes of data can be transformed
+// without splitting a block or a UTF-8 character
+func alignedLength(data []byte, config StreamingConfig) int {
+	n := len(data)
+	if config.BlockSize > 1 {
+		n -= n % config.BlockSize
+	}
+	if config.RuneAligned {
+		// walk back to the start of the last rune and keep it if incomplete
+		for i := n - 1; i >= 0 && i >= n-utf8.UTFMax; i-- {
+			if utf8.RuneStart(data[i]) {
+				if !utf8.FullRune(data[i:n]) {
+					n = i
+				}
+				break
+			}
+		}
+	}
+	return n
+}
+
+// transformChunk transforms a single chunk and writes the result
+func transformChunk(processor Processor, chunk []byte, writer io.Writer, opts ...Flag) error {
+	result, err := processor.Transform(chunk, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(writer, result)
+	return err
+}
+
 // CanStream returns true if a processor can handle streaming
 // All processors can stream using the central TransformStream function
 func CanStream(processor Processor) bool {
@@ -248,18 +377,14 @@ func CanStream(processor Processor) bool {
 // PreferStream returns true if a processor benefits from streaming
 // This is useful for large files or processors that don't need full input
 func PreferStream(processor Processor) bool {
+	// Native streaming implementations know best
+	if sp, ok := processor.(StreamingProcessor); ok {
+		return sp.PreferStream()
+	}
+
 	if sp, ok := processor.(ConfigurableStreamingProcessor); ok {
 		config := sp.GetStreamingConfig()
 
-		name := processor.Name()
-
-		hashFunctions := []string{"md5", "sha1", "sha224", "sha256", "sha384", "sha512"}
-		for _, hash := range hashFunctions {
-			if name == hash {
-				return true
-			}
-		}
-
 		if !config.BufferOutput {
 			return true
 		}
@@ -271,15 +396,9 @@ func PreferStream(processor Processor) bool {
 		return false
 	}
 
-	// Check if processor implements the old StreamingProcessor interface
-	if sp, ok := processor.(StreamingProcessor); ok {
-		return sp.PreferStream()
-	}
-
-	// Default: prefer streaming for hash functions and encoders
+	// Default: prefer streaming for encoders
 	name := processor.Name()
 	streamingFriendly := []string{
-		"md5", "sha1", "sha224", "sha256", "sha384", "sha512",
 		"hex-encode", "hex-decode", "base64-encode", "base64-decode",
 		"base32-encode", "base32-decode", "upper", "lower",
 	}
@@ -298,7 +417,8 @@ func PreferStream(processor Processor) bool {
 // Example implements 'Item' and 'DefaultItem' from package 'github.com/charmbracelet/bubbles/list'
 // to work with the UI, and `Processor` from this package to do the text transformation and generation
 // of the CLI commands
-// After implementing add the struct to List.
+// After implementing add the struct to List, processors living outside this
+// package can be added with Register instead.
 type Zeropad struct{}
 
 func (p Zeropad) Name() string {
@@ -321,21 +441,12 @@ func (p Zeropad) Transform(data []byte, f ...Flag) (string, error) {
 		data = data[1:]
 	}
 
-	var n int
-	pre := ""
-	for _, flag := range f {
-		if flag.Short == "n" {
-			x, ok := flag.Value.(uint)
-			if ok {
-				n = int(x)
-			}
-		} else if flag.Short == "p" {
-			x, ok := flag.Value.(string)
-			if ok {
-				pre = x
-			}
-		}
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return "", err
 	}
+	n := int(flags.Uint("number-of-zeros"))
+	pre := flags.String("prefix")
 	return fmt.Sprintf("%s%s%s%s", pre, neg, strings.Repeat("0", n), data), nil
 }
 
@@ -367,6 +478,10 @@ func (p Zeropad) Description() string {
 	return "Pad a number with zeros"
 }
 
+func (p Zeropad) Category() string {
+	return CategoryOther
+}
+
 func (p Zeropad) FilterValue() string {
 	return p.Title()
 }
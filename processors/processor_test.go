@@ -1,6 +1,7 @@
 package processors
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 )
@@ -79,14 +80,19 @@ func TestZeropad_Transform(t *testing.T) {
 			want: "-0000012",
 		},
 		{
-			name: "Should return no zero's INT 5",
+			name: "Should return 5 zero's on INT 5",
 			args: args{input: "12", f: []Flag{{Short: "n", Value: 5}}},
-			want: "12",
+			want: "0000012",
+		},
+		{
+			name:    "Should fail on -1",
+			args:    args{input: "12", f: []Flag{{Short: "n", Value: -1}}},
+			wantErr: true,
 		},
 		{
-			name: "Should return no zero's -1",
-			args: args{input: "12", f: []Flag{{Short: "n", Value: -1}}},
-			want: "12",
+			name:    "Should fail on unknown flag",
+			args:    args{input: "12", f: []Flag{{Short: "x", Value: "A"}}},
+			wantErr: true,
 		},
 		{
 			name: "Should return 5 with prefix zero's on UNIT 5",
@@ -119,3 +125,55 @@ func TestZeropad_Transform(t *testing.T) {
 		})
 	}
 }
+
+func TestIsDigest(t *testing.T) {
+	digests := []Processor{MD5{}, SHA1{}, SHA224{}, SHA256{}, SHA384{}, SHA512{}, CRC32{}, Adler32{}, XXH32{}, XXH64{}, XXH128{}, BLAKE2b{}, BLAKE2s{}}
+	for _, p := range digests {
+		if !IsDigest(p) {
+			t.Errorf("IsDigest(%s) = false, want true", p.Name())
+		}
+	}
+
+	// bcrypt output is salted, so it isn't a digest of the input alone
+	for _, p := range []Processor{Upper{}, Base64Encode{}, Bcrypt{}} {
+		if IsDigest(p) {
+			t.Errorf("IsDigest(%s) = true, want false", p.Name())
+		}
+	}
+}
+
+func TestTransformBytes(t *testing.T) {
+	tests := []struct {
+		p    Processor
+		in   string
+		want []byte
+	}{
+		{p: Base64Decode{}, in: "/w==", want: []byte{0xff}},
+		{p: Base64URLDecode{}, in: "_w==", want: []byte{0xff}},
+		{p: Base32Decode{}, in: "74======", want: []byte{0xff}},
+		{p: HexDecode{}, in: "ff00", want: []byte{0xff, 0x00}},
+		{p: Base58Decode{}, in: "5Q", want: []byte{0xff}},
+		{p: JSONToMSGPACK{}, in: "true", want: []byte{0xc3}},
+		{p: Upper{}, in: "abc", want: []byte("ABC")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.p.Name(), func(t *testing.T) {
+			got, err := TransformBytes(tt.p, []byte(tt.in))
+			if err != nil {
+				t.Fatalf("TransformBytes() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("TransformBytes() = %x, want %x", got, tt.want)
+			}
+			out, err := tt.p.Transform([]byte(tt.in))
+			if err != nil || out != string(got) {
+				t.Errorf("Transform() = %q, %v, want %q", out, err, got)
+			}
+		})
+	}
+
+	if !IsBinary(HexDecode{}) || IsBinary(HexEncode{}) {
+		t.Error("IsBinary() should only be true for processors with binary output")
+	}
+}
@@ -25,38 +25,36 @@ func (p QRCode) Transform(data []byte, f ...Flag) (string, error) {
 		QuietZone:  1,
 	}
 
-	for _, flag := range f {
-		switch flag.Short {
-		case "s":
-			if size, ok := flag.Value.(uint); ok {
-				if size >= 300 {
-					config.QuietZone = 3
-					config.Level = qrterminal.H
-				} else if size >= 200 {
-					config.QuietZone = 2
-					config.Level = qrterminal.M
-				} else {
-					config.QuietZone = 1
-					config.Level = qrterminal.L
-				}
-			}
-		case "l":
-			if level, ok := flag.Value.(string); ok {
-				switch level {
-				case "L", "low":
-					config.Level = qrterminal.L
-				case "M", "medium":
-					config.Level = qrterminal.M
-				case "H", "high":
-					config.Level = qrterminal.H
-				}
-			}
-		case "f":
-			if full, ok := flag.Value.(bool); ok && full {
-				config.HalfBlocks = false
-			}
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return "", err
+	}
+
+	if flags.IsSet("size") {
+		if size := flags.Uint("size"); size >= 300 {
+			config.QuietZone = 3
+			config.Level = qrterminal.H
+		} else if size >= 200 {
+			config.QuietZone = 2
+			config.Level = qrterminal.M
+		} else {
+			config.QuietZone = 1
+			config.Level = qrterminal.L
 		}
 	}
+	if flags.IsSet("level") {
+		switch flags.String("level") {
+		case "L", "low":
+			config.Level = qrterminal.L
+		case "M", "medium":
+			config.Level = qrterminal.M
+		case "H", "high":
+			config.Level = qrterminal.H
+		}
+	}
+	if flags.Bool("full") {
+		config.HalfBlocks = false
+	}
 
 	var buf bytes.Buffer
 	config.Writer = &buf
@@ -76,11 +74,12 @@ func (p QRCode) Flags() []Flag {
 			Type:  FlagUint,
 		},
 		{
-			Name:  "level",
-			Short: "l",
-			Desc:  "Error correction level (L/low, M/medium, H/high)",
-			Value: "H",
-			Type:  FlagString,
+			Name:    "level",
+			Short:   "l",
+			Desc:    "Error correction level (L/low, M/medium, H/high)",
+			Value:   "H",
+			Type:    FlagString,
+			Choices: []string{"L", "M", "H", "low", "medium", "high"},
 		},
 		{
 			Name:  "full",
@@ -100,6 +99,14 @@ func (p QRCode) Description() string {
 	return "Generate QR code in terminal"
 }
 
+func (p QRCode) Category() string {
+	return CategoryGenerators
+}
+
+func (p QRCode) Tags() []string {
+	return []string{"qrcode", "barcode"}
+}
+
 func (p QRCode) FilterValue() string {
 	return p.Title()
 }
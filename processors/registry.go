@@ -0,0 +1,108 @@
+package processors
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+var (
+	registryMu sync.RWMutex
+	// registered holds every processor in registration order
+	registered []Processor
+	// registryNames maps names and aliases to their processor
+	registryNames = make(map[string]Processor)
+)
+
+var validName = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+func init() {
+	for _, item := range List {
+		p, ok := item.(Processor)
+		if !ok {
+			panic(fmt.Sprintf("item is not a processor: %v", item))
+		}
+		if err := Register(p); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Register adds a processor to the registry so it can be found by Lookup
+// and All. It fails when the name or one of the aliases is not a lowercase
+// word (hyphens allowed) or is already used by another processor.
+func Register(p Processor) error {
+	name := p.Name()
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid processor name %q", name)
+	}
+
+	names := []string{name}
+	for _, alias := range p.Alias() {
+		if !validName.MatchString(alias) {
+			return fmt.Errorf("processor %s: invalid alias %q", name, alias)
+		}
+		if alias != name {
+			names = append(names, alias)
+		}
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for i, n := range names {
+		if other, ok := registryNames[n]; ok {
+			return fmt.Errorf("processor %s: %q is already used by %s", name, n, other.Name())
+		}
+		for _, prev := range names[:i] {
+			if prev == n {
+				return fmt.Errorf("processor %s: duplicate alias %q", name, n)
+			}
+		}
+	}
+
+	for _, n := range names {
+		registryNames[n] = p
+	}
+	registered = append(registered, p)
+	return nil
+}
+
+// Lookup returns the processor registered with the given name or alias
+func Lookup(name string) (Processor, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	p, ok := registryNames[name]
+	return p, ok
+}
+
+// All returns every registered processor in registration order
+func All() []Processor {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]Processor(nil), registered...)
+}
+
+// TitleOf returns the Title of a processor, processors which don't implement
+// it get a title derived from their name
+func TitleOf(p Processor) string {
+	if t, ok := p.(interface{ Title() string }); ok {
+		return t.Title()
+	}
+	title := cases.Title(language.Und, cases.NoLower).String(p.Name())
+	return fmt.Sprintf("%s (%s)", title, p.Name())
+}
+
+// DescriptionOf returns the Description of a processor or an empty string
+// when the processor doesn't implement it
+func DescriptionOf(p Processor) string {
+	if d, ok := p.(interface{ Description() string }); ok {
+		return d.Description()
+	}
+	return ""
+}
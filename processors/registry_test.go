@@ -0,0 +1,119 @@
+package processors
+
+import (
+	"strings"
+	"testing"
+)
+
+type registryTestProcessor struct {
+	name  string
+	alias []string
+}
+
+func (p registryTestProcessor) Name() string    { return p.name }
+func (p registryTestProcessor) Alias() []string { return p.alias }
+func (p registryTestProcessor) Flags() []Flag   { return nil }
+func (p registryTestProcessor) Transform(data []byte, _ ...Flag) (string, error) {
+	return string(data), nil
+}
+
+func TestRegistry_BuiltinProcessors(t *testing.T) {
+	all := All()
+	if len(all) < len(List) {
+		t.Fatalf("All() returned %d processors, want at least %d", len(all), len(List))
+	}
+	for i, item := range List {
+		p := item.(Processor)
+		if all[i].Name() != p.Name() {
+			t.Errorf("All()[%d] = %s, want %s", i, all[i].Name(), p.Name())
+		}
+		got, ok := Lookup(p.Name())
+		if !ok || got.Name() != p.Name() {
+			t.Errorf("Lookup(%q) = %v, %v", p.Name(), got, ok)
+		}
+		for _, alias := range p.Alias() {
+			got, ok := Lookup(alias)
+			if !ok || got.Name() != p.Name() {
+				t.Errorf("Lookup(%q) = %v, %v, want %s", alias, got, ok, p.Name())
+			}
+		}
+	}
+
+	if _, ok := Lookup("does-not-exist"); ok {
+		t.Errorf("Lookup() found a processor that does not exist")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name      string
+		processor Processor
+		wantErr   string
+	}{
+		{
+			name:      "Name already used",
+			processor: registryTestProcessor{name: "md5"},
+			wantErr:   `"md5" is already used by md5`,
+		},
+		{
+			name:      "Alias already used",
+			processor: registryTestProcessor{name: "registry-test-a", alias: []string{"b64-dec"}},
+			wantErr:   `"b64-dec" is already used by base64-decode`,
+		},
+		{
+			name:      "Duplicate alias",
+			processor: registryTestProcessor{name: "registry-test-b", alias: []string{"rt", "rt"}},
+			wantErr:   `duplicate alias "rt"`,
+		},
+		{
+			name:      "Invalid name",
+			processor: registryTestProcessor{name: "Not Valid"},
+			wantErr:   `invalid processor name "Not Valid"`,
+		},
+		{
+			name:      "Invalid alias",
+			processor: registryTestProcessor{name: "registry-test-c", alias: []string{"under_score"}},
+			wantErr:   `invalid alias "under_score"`,
+		},
+		{
+			name:      "Valid processor",
+			processor: registryTestProcessor{name: "registry-test", alias: []string{"registry-test", "rtest"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Register(tt.processor)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Register() error = %v, want %q", err, tt.wantErr)
+				}
+				if p, ok := Lookup(tt.processor.Name()); ok && p == tt.processor {
+					t.Errorf("Register() added a rejected processor")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			if p, ok := Lookup("rtest"); !ok || p.Name() != tt.processor.Name() {
+				t.Errorf("Lookup() = %v, %v after Register()", p, ok)
+			}
+		})
+	}
+}
+
+func TestTitleAndDescriptionOf(t *testing.T) {
+	if got := TitleOf(MD5{}); got != "MD5 Sum (md5)" {
+		t.Errorf("TitleOf() = %v", got)
+	}
+	if got := TitleOf(registryTestProcessor{name: "plain-name"}); got != "Plain-Name (plain-name)" {
+		t.Errorf("TitleOf() = %v", got)
+	}
+	if got := DescriptionOf(MD5{}); got != "Get the MD5 checksum of your text" {
+		t.Errorf("DescriptionOf() = %v", got)
+	}
+	if got := DescriptionOf(registryTestProcessor{name: "plain-name"}); got != "" {
+		t.Errorf("DescriptionOf() = %v", got)
+	}
+}
@@ -0,0 +1,154 @@
+package processors
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Request is a transformation asked by a client of sttr serve, rpc, mcp or
+// lsp, they all run it with Run
+type Request struct {
+	// Processor runs first with Flags, either Processor or Chain can be omitted
+	Processor Processor
+	Flags     []Flag
+	// Chain runs on the output of Processor, its steps hold their flags
+	Chain Chain
+	// Input is encoded with Encoding, utf-8 (default) or base64
+	Input    string
+	Encoding string
+	// Defaults returns the configured defaults of a processor, they come
+	// before Flags so the flags of the request win. It can be nil.
+	Defaults func(name string) []Flag
+	// Slots bounds the transformations running at once among the requests
+	// sharing it, its capacity is their number. A slot is held until the
+	// steps return, after ctx is done too. Nil doesn't bound them.
+	Slots chan struct{}
+}
+
+// Result is the output of a Request
+type Result struct {
+	// Chain holds the steps which ran, Processor first with its flags
+	Chain  Chain
+	Output []byte
+	// Result is Output as a string, base64 encoded when Encoding is base64
+	// because Output is not text
+	Result   string
+	Encoding string
+}
+
+// RequestError is an error of Run, Kind is usage, input or transform like
+// the sttr command line reports
+type RequestError struct {
+	Kind string
+	Err  error
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// Steps returns the chain run by r, Processor with the configured defaults
+// and its flags followed by Chain
+func (r Request) Steps() Chain {
+	var chain Chain
+	if r.Processor != nil {
+		var flags []Flag
+		if r.Defaults != nil {
+			flags = r.Defaults(r.Processor.Name())
+		}
+		chain = append(chain, ChainStep{Processor: r.Processor, Flags: append(flags, r.Flags...)})
+	}
+	return append(chain, r.Chain...)
+}
+
+// DecodeInput returns input decoded from encoding, utf-8 (or empty) or base64
+func DecodeInput(input, encoding string) ([]byte, error) {
+	switch encoding {
+	case "", "utf-8":
+		return []byte(input), nil
+	case "base64":
+		in, err := base64.StdEncoding.DecodeString(input)
+		if err != nil {
+			return nil, &RequestError{Kind: "input", Err: fmt.Errorf("invalid base64 input: %w", err)}
+		}
+		return in, nil
+	default:
+		return nil, &RequestError{Kind: "usage", Err: fmt.Errorf("invalid encoding %q, must be one of: utf-8, base64", encoding)}
+	}
+}
+
+// IsText reports whether data is text: valid UTF-8 without NUL bytes
+func IsText(data []byte) bool {
+	return utf8.Valid(data) && bytes.IndexByte(data, 0) < 0
+}
+
+// EncodeOutput returns out as a string and its encoding, utf-8 for text and
+// base64 for binary data
+func EncodeOutput(out []byte) (string, string) {
+	if !IsText(out) {
+		return base64.StdEncoding.EncodeToString(out), "base64"
+	}
+	return string(out), "utf-8"
+}
+
+// Run decodes the input of r, runs its steps on it and encodes the output.
+// Errors are a *RequestError, or the error of ctx when it is done first,
+// waiting for one of the Slots included; the steps keep running in the
+// background until they return.
+func Run(ctx context.Context, r Request) (*Result, error) {
+	chain := r.Steps()
+	if len(chain) == 0 {
+		return nil, &RequestError{Kind: "usage", Err: errors.New("processor or chain is required")}
+	}
+	in, err := DecodeInput(r.Input, r.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		out []byte
+		err error
+	}
+	if r.Slots != nil {
+		select {
+		case r.Slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	done := make(chan result, 1)
+	go func() {
+		if r.Slots != nil {
+			defer func() { <-r.Slots }()
+		}
+		var res result
+		if len(chain) == 1 {
+			res.out, res.err = TransformBytes(chain[0].Processor, in, chain[0].Flags...)
+		} else {
+			var out string
+			out, res.err = chain.Transform(in)
+			res.out = []byte(out)
+		}
+		done <- res
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return nil, &RequestError{Kind: "transform", Err: res.err}
+		}
+		result := &Result{Chain: chain, Output: res.out}
+		result.Result, result.Encoding = EncodeOutput(res.out)
+		return result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,168 @@
+package processors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	defaults := func(name string) []Flag {
+		if name == "zeropad" {
+			return []Flag{{Name: "number-of-zeros", Short: "n", Value: uint(3)}}
+		}
+		return nil
+	}
+	upper, _ := ParseChain("upper")
+
+	tests := []struct {
+		name         string
+		req          Request
+		want         string
+		wantEncoding string
+		wantCommand  string
+		wantKind     string
+	}{
+		{
+			name:         "Processor",
+			req:          Request{Processor: Upper{}, Input: "hello"},
+			want:         "HELLO",
+			wantEncoding: "utf-8",
+			wantCommand:  "sttr upper",
+		},
+		{
+			name:         "Configured defaults",
+			req:          Request{Processor: Zeropad{}, Input: "1", Defaults: defaults},
+			want:         "0001",
+			wantEncoding: "utf-8",
+			wantCommand:  "sttr zeropad -n 3",
+		},
+		{
+			name:         "Flags win over the configured defaults",
+			req:          Request{Processor: Zeropad{}, Flags: []Flag{{Name: "number-of-zeros", Short: "n", Value: uint(1)}}, Input: "1", Defaults: defaults},
+			want:         "01",
+			wantEncoding: "utf-8",
+		},
+		{
+			name:         "Processor and chain",
+			req:          Request{Processor: Base64Decode{}, Chain: upper, Input: "aGk="},
+			want:         "HI",
+			wantEncoding: "utf-8",
+			wantCommand:  "sttr base64-decode | sttr upper",
+		},
+		{
+			name:         "Base64 input",
+			req:          Request{Chain: upper, Input: "aGk=", Encoding: "base64"},
+			want:         "HI",
+			wantEncoding: "utf-8",
+			wantCommand:  "sttr upper",
+		},
+		{
+			name:         "Binary output",
+			req:          Request{Processor: HexDecode{}, Input: "00ff"},
+			want:         "AP8=",
+			wantEncoding: "base64",
+			wantCommand:  "sttr hex-decode",
+		},
+		{
+			name:         "Output with NUL bytes",
+			req:          Request{Processor: Base64Decode{}, Input: "AAE="},
+			want:         "AAE=",
+			wantEncoding: "base64",
+		},
+		{name: "Nothing to run", req: Request{Input: "x"}, wantKind: "usage"},
+		{name: "Invalid encoding", req: Request{Processor: Upper{}, Encoding: "hex"}, wantKind: "usage"},
+		{name: "Invalid base64 input", req: Request{Processor: Upper{}, Input: "!", Encoding: "base64"}, wantKind: "input"},
+		{name: "Transform error", req: Request{Processor: FormatJSON{}, Input: "{bad"}, wantKind: "transform"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Run(context.Background(), tt.req)
+			var reqErr *RequestError
+			if tt.wantKind != "" {
+				if !errors.As(err, &reqErr) || reqErr.Kind != tt.wantKind {
+					t.Fatalf("Run() error = %v, want a %s error", err, tt.wantKind)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if res.Result != tt.want || res.Encoding != tt.wantEncoding {
+				t.Errorf("Run() = %q %s, want %q %s", res.Result, res.Encoding, tt.want, tt.wantEncoding)
+			}
+			if got := res.Chain.CommandLine(); tt.wantCommand != "" && got != tt.wantCommand {
+				t.Errorf("Run() chain = %s, want %s", got, tt.wantCommand)
+			}
+		})
+	}
+}
+
+func TestRun_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Run(ctx, Request{Processor: Upper{}, Input: "x"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+// blockingStep doesn't return until release is closed
+type blockingStep struct {
+	release chan struct{}
+}
+
+func (p blockingStep) Name() string    { return "test-block" }
+func (p blockingStep) Alias() []string { return nil }
+func (p blockingStep) Flags() []Flag   { return nil }
+func (p blockingStep) Transform(data []byte, _ ...Flag) (string, error) {
+	<-p.release
+	return string(data), nil
+}
+
+func TestRun_Slots(t *testing.T) {
+	slots := make(chan struct{}, 1)
+	block := blockingStep{release: make(chan struct{})}
+	run := func(p Processor) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := Run(ctx, Request{Processor: p, Input: "x", Slots: slots})
+		return err
+	}
+
+	if err := run(block); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	// the timed out transformation still holds the only slot
+	if err := run(Upper{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() error = %v while the slot is held, want %v", err, context.DeadlineExceeded)
+	}
+
+	close(block.release)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(slots) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := run(Upper{}); err != nil {
+		t.Errorf("Run() error = %v after the slot was released", err)
+	}
+}
+
+func TestIsText(t *testing.T) {
+	tests := []struct {
+		data string
+		want bool
+	}{
+		{data: "", want: true},
+		{data: "hello\n\tworld", want: true},
+		{data: "héllo ✓", want: true},
+		{data: "\x00\x01"},
+		{data: "a\x00b"},
+		{data: "\xff"},
+	}
+	for _, tt := range tests {
+		if got := IsText([]byte(tt.data)); got != tt.want {
+			t.Errorf("IsText(%q) = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
@@ -36,6 +36,14 @@ func (p HexToRGB) Description() string {
 	return "Convert a #hex-color code to RGB"
 }
 
+func (p HexToRGB) Category() string {
+	return CategoryColor
+}
+
+func (p HexToRGB) Tags() []string {
+	return []string{"convert"}
+}
+
 func (p HexToRGB) FilterValue() string {
 	return p.Title()
 }
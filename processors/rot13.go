@@ -34,6 +34,14 @@ func (p ROT13) Description() string {
 	return "Cipher/Decipher your text with ROT13 letter substitution"
 }
 
+func (p ROT13) Category() string {
+	return CategoryEncoding
+}
+
+func (p ROT13) Tags() []string {
+	return []string{"cipher", "caesar"}
+}
+
 func (p ROT13) FilterValue() string {
 	return p.Title()
 }
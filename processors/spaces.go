@@ -18,14 +18,14 @@ func (p RemoveNewLines) Alias() []string {
 }
 
 func (p RemoveNewLines) Transform(data []byte, f ...Flag) (string, error) {
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return "", err
+	}
+	// without the flag lines are joined by a space, as they always were
 	separator := " "
-	for _, flag := range f {
-		if flag.Short == "s" {
-			x, ok := flag.Value.(string)
-			if ok {
-				separator = x
-			}
-		}
+	if flags.IsSet("separator") {
+		separator = flags.String("separator")
 	}
 
 	str := regexp.MustCompile(`[\r\n]+`).
@@ -54,6 +54,10 @@ func (p RemoveNewLines) Description() string {
 	return "Remove all new lines"
 }
 
+func (p RemoveNewLines) Category() string {
+	return CategoryLines
+}
+
 func (p RemoveNewLines) FilterValue() string {
 	return p.Title()
 }
@@ -70,15 +74,11 @@ func (p RemoveSpaces) Alias() []string {
 }
 
 func (p RemoveSpaces) Transform(data []byte, f ...Flag) (string, error) {
-	separator := ""
-	for _, flag := range f {
-		if flag.Short == "s" {
-			x, ok := flag.Value.(string)
-			if ok {
-				separator = x
-			}
-		}
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return "", err
 	}
+	separator := flags.String("separator")
 
 	str := regexp.MustCompile(`[\s\r\n]+`).
 		ReplaceAllString(strings.TrimSpace(string(data)), separator)
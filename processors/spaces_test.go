@@ -86,6 +86,11 @@ func TestRemoveNewLines_Transform(t *testing.T) {
 			args: args{data: []byte("1\r\n2\r\n3\r\n4"), opts: []Flag{{Short: "s", Value: ","}}},
 			want: "1,2,3,4",
 		},
+		{
+			name: "Remove newlines with the empty default separator",
+			args: args{data: []byte("1\r\n2\r\n3\r\n4"), opts: []Flag{{Short: "s", Value: ""}}},
+			want: "1234",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1,9 +1,14 @@
 package processors
 
 import (
+	"encoding/hex"
+	"hash"
 	"io"
 )
 
+// StreamingProcessor is an optional interface for processors with a native
+// incremental implementation, when CanStream returns true TransformStream
+// always takes precedence over the generic chunked or buffered fallbacks
 type StreamingProcessor interface {
 	Processor
 
@@ -52,3 +57,20 @@ func (d DefaultStreamingProcessor) Flags() []Flag       { return nil }
 func (d DefaultStreamingProcessor) Title() string       { return "Default" }
 func (d DefaultStreamingProcessor) Description() string { return "Default processor" }
 func (d DefaultStreamingProcessor) FilterValue() string { return "Default" }
+
+// hashHex returns the hex encoded digest of data
+func hashHex(h hash.Hash, data []byte) string {
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashStream feeds reader into h and writes the hex encoded digest to writer,
+// memory usage is bound to the hash state no matter how large the input is
+func hashStream(h hash.Hash, reader io.Reader, writer io.Writer) error {
+	if _, err := io.Copy(h, reader); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(writer, hex.EncodeToString(h.Sum(nil)))
+	return err
+}
@@ -2,7 +2,9 @@ package processors
 
 import (
 	"bytes"
+	"fmt"
 	"io"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -872,3 +874,339 @@ func TestRealWorldLargeFile(t *testing.T) {
 		})
 	}
 }
+
+// nativeStreamProcessor records whether its own TransformStream was used
+type nativeStreamProcessor struct {
+	DefaultStreamingProcessor
+	called *bool
+}
+
+func (p nativeStreamProcessor) CanStream() bool { return true }
+
+func (p nativeStreamProcessor) GetStreamingConfig() StreamingConfig {
+	return StreamingConfig{ChunkSize: 1, BufferOutput: false}
+}
+
+func (p nativeStreamProcessor) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
+	*p.called = true
+	_, err := io.Copy(writer, reader)
+	return err
+}
+
+func TestTransformStreamPrefersNativeImplementation(t *testing.T) {
+	called := false
+	p := nativeStreamProcessor{called: &called}
+
+	var writer bytes.Buffer
+	if err := TransformStream(p, strings.NewReader("hello"), &writer); err != nil {
+		t.Fatalf("TransformStream() error = %v", err)
+	}
+	if !called {
+		t.Errorf("TransformStream() did not use the native implementation")
+	}
+	if got := writer.String(); got != "hello" {
+		t.Errorf("TransformStream() = %v, want %v", got, "hello")
+	}
+}
+
+func TestHashNativeStreaming(t *testing.T) {
+	hashes := []struct {
+		processor Processor
+		flags     []Flag
+	}{
+		{processor: MD5{}},
+		{processor: SHA1{}},
+		{processor: SHA224{}},
+		{processor: SHA256{}},
+		{processor: SHA384{}},
+		{processor: SHA512{}},
+		{processor: CRC32{}},
+		{processor: CRC32{}, flags: []Flag{{Short: "p", Value: "castagnoli"}}},
+		{processor: Adler32{}},
+		{processor: BLAKE2b{}},
+		{processor: BLAKE2b{}, flags: []Flag{{Short: "s", Value: uint(32)}}},
+		{processor: BLAKE2s{}},
+		{processor: XXH32{}},
+		{processor: XXH64{}},
+		{processor: XXH128{}},
+	}
+
+	inputs := []string{"", "a", "hello world", strings.Repeat("sttr streaming ", 10000)}
+
+	for _, h := range hashes {
+		if _, ok := h.processor.(StreamingProcessor); !ok {
+			t.Errorf("%s does not implement StreamingProcessor", h.processor.Name())
+			continue
+		}
+		if !PreferStream(h.processor) {
+			t.Errorf("PreferStream() = false, want true for %s", h.processor.Name())
+		}
+
+		for _, input := range inputs {
+			t.Run(h.processor.Name(), func(t *testing.T) {
+				want, err := h.processor.Transform([]byte(input), h.flags...)
+				if err != nil {
+					t.Fatalf("Transform() error = %v", err)
+				}
+
+				reader := &chunkReader{data: []byte(input), chunkSize: 7}
+				var writer bytes.Buffer
+				if err := TransformStream(h.processor, reader, &writer, h.flags...); err != nil {
+					t.Fatalf("TransformStream() error = %v", err)
+				}
+				if got := writer.String(); got != want {
+					t.Errorf("TransformStream() = %v, want %v", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestHashStreamingConstantMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping constant memory test in short mode")
+	}
+
+	const size = 256 * 1024 * 1024 // 256MB
+
+	for _, p := range []Processor{SHA256{}, XXH128{}, CRC32{}} {
+		t.Run(p.Name(), func(t *testing.T) {
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+
+			reader := io.LimitReader(zeroReader{}, size)
+			if err := TransformStream(p, reader, io.Discard); err != nil {
+				t.Fatalf("TransformStream() error = %v", err)
+			}
+
+			runtime.ReadMemStats(&after)
+			if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 4*1024*1024 {
+				t.Errorf("TransformStream() allocated %d bytes for a %d byte input", allocated, size)
+			}
+		})
+	}
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestOddSizedReadsMatchTransform(t *testing.T) {
+	text := strings.Repeat("héllo wörld ✓ 日本語 sttr\n", 50)
+	encode := func(p Processor, f ...Flag) string {
+		out, err := p.Transform([]byte(text), f...)
+		if err != nil {
+			t.Fatalf("%s Transform() error = %v", p.Name(), err)
+		}
+		return out
+	}
+	raw := []Flag{{Short: "r", Value: true}}
+
+	tests := []struct {
+		processor Processor
+		input     string
+		flags     []Flag
+	}{
+		{processor: Upper{}, input: text},
+		{processor: Lower{}, input: strings.ToUpper(text)},
+		{processor: HexEncode{}, input: text},
+		{processor: HexDecode{}, input: encode(HexEncode{})},
+		{processor: Base64Encode{}, input: text},
+		{processor: Base64Encode{}, input: text, flags: raw},
+		{processor: Base64Decode{}, input: encode(Base64Encode{})},
+		{processor: Base64Decode{}, input: encode(Base64Encode{}, raw...), flags: raw},
+		{processor: Base64URLEncode{}, input: text},
+		{processor: Base64URLDecode{}, input: encode(Base64URLEncode{})},
+		{processor: Base32Encoding{}, input: text},
+		{processor: Base32Decode{}, input: encode(Base32Encoding{})},
+		{processor: ASCII85Encoding{}, input: text},
+		{processor: ASCII85Decoding{}, input: encode(ASCII85Encoding{})},
+		{processor: MD5{}, input: text},
+		{processor: SHA1{}, input: text},
+		{processor: SHA224{}, input: text},
+		{processor: SHA256{}, input: text},
+		{processor: SHA384{}, input: text},
+		{processor: SHA512{}, input: text},
+		{processor: CRC32{}, input: text},
+		{processor: Adler32{}, input: text},
+		{processor: BLAKE2b{}, input: text},
+		{processor: BLAKE2s{}, input: text},
+		{processor: XXH32{}, input: text},
+		{processor: XXH64{}, input: text},
+		{processor: XXH128{}, input: text},
+	}
+
+	covered := make(map[string]bool)
+	for _, tt := range tests {
+		covered[tt.processor.Name()] = true
+	}
+	for _, item := range List {
+		p := item.(Processor)
+		if PreferStream(p) && !covered[p.Name()] {
+			t.Errorf("streaming friendly processor %s has no odd sized read test", p.Name())
+		}
+	}
+
+	for _, tt := range tests {
+		want, err := tt.processor.Transform([]byte(tt.input), tt.flags...)
+		if err != nil {
+			t.Fatalf("%s Transform() error = %v", tt.processor.Name(), err)
+		}
+
+		for _, size := range []int{1, 2, 3, 5, 7, 11, 64*1024 + 1} {
+			t.Run(fmt.Sprintf("%s_%d", tt.processor.Name(), size), func(t *testing.T) {
+				reader := &chunkReader{data: []byte(tt.input), chunkSize: size}
+				var writer bytes.Buffer
+				if err := TransformStream(tt.processor, reader, &writer, tt.flags...); err != nil {
+					t.Fatalf("TransformStream() error = %v", err)
+				}
+				if got := writer.String(); got != want {
+					t.Errorf("TransformStream() = %q, want %q", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestAlignedLength(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []byte
+		config StreamingConfig
+		want   int
+	}{
+		{name: "No alignment", data: []byte("abcde"), want: 5},
+		{name: "Block of 3", data: []byte("abcde"), config: StreamingConfig{BlockSize: 3}, want: 3},
+		{name: "Shorter than a block", data: []byte("ab"), config: StreamingConfig{BlockSize: 3}, want: 0},
+		{name: "Complete rune", data: []byte("aé"), config: StreamingConfig{RuneAligned: true}, want: 3},
+		{name: "Split rune", data: []byte("a日")[:3], config: StreamingConfig{RuneAligned: true}, want: 1},
+		{name: "Only a split rune", data: []byte("日")[:2], config: StreamingConfig{RuneAligned: true}, want: 0},
+		{name: "Invalid byte", data: []byte{'a', 0xff}, config: StreamingConfig{RuneAligned: true}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := alignedLength(tt.data, tt.config); got != tt.want {
+				t.Errorf("alignedLength() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTransformStream_NoConfigIsBuffered checks processors without a
+// streaming configuration see the whole input, whatever the reads
+func TestTransformStream_NoConfigIsBuffered(t *testing.T) {
+	text := strings.Repeat(`{"héllo": ["wörld", 1, true]} `, 5000)
+	tests := []struct {
+		processor Processor
+		input     string
+	}{
+		{processor: Reverse{}, input: text},
+		{processor: FormatJSON{}, input: `[` + strings.Repeat(`{"a": "日本語"},`, 10000) + `{}]`},
+		{processor: JSONToYAML{}, input: `{"a": "` + strings.Repeat("ü", 50000) + `"}`},
+	}
+	for _, tt := range tests {
+		want, err := tt.processor.Transform([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("%s Transform() error = %v", tt.processor.Name(), err)
+		}
+		for _, size := range []int{1, 7, 4093} {
+			t.Run(fmt.Sprintf("%s_%d", tt.processor.Name(), size), func(t *testing.T) {
+				var writer bytes.Buffer
+				reader := &chunkReader{data: []byte(tt.input), chunkSize: size}
+				if err := TransformStream(tt.processor, reader, &writer); err != nil {
+					t.Fatalf("TransformStream() error = %v", err)
+				}
+				if writer.String() != want {
+					t.Errorf("TransformStream() differs from Transform() on a %d bytes input", len(tt.input))
+				}
+			})
+		}
+	}
+}
+
+// TestTransformStream_InvalidInput makes sure a decoder failing at the end
+// of its input writes nothing, so in-place and batch runs keep no partial file
+func TestTransformStream_InvalidInput(t *testing.T) {
+	input := []byte(strings.Repeat("68656c6c6f", 20000) + "zz")
+	for _, size := range []int{1, 7, 4093, 64 * 1024} {
+		t.Run(fmt.Sprintf("hex-decode_%d", size), func(t *testing.T) {
+			var writer bytes.Buffer
+			reader := &chunkReader{data: input, chunkSize: size}
+			if err := TransformStream(HexDecode{}, reader, &writer); err == nil {
+				t.Fatal("TransformStream() error = nil, want an invalid byte error")
+			}
+			if writer.Len() != 0 {
+				t.Errorf("TransformStream() wrote %d bytes before failing, want none", writer.Len())
+			}
+		})
+	}
+}
+
+// lineStreamProcessor is streamed line by line
+type lineStreamProcessor struct {
+	DefaultStreamingProcessor
+}
+
+func (p lineStreamProcessor) GetStreamingConfig() StreamingConfig {
+	return StreamingConfig{LineByLine: true}
+}
+
+func TestStreamingModeOf(t *testing.T) {
+	tests := []struct {
+		processor Processor
+		want      StreamingMode
+	}{
+		{processor: nativeStreamProcessor{}, want: StreamNative},
+		{processor: ASCII85Encoding{}, want: StreamNative},
+		{processor: lineStreamProcessor{}, want: StreamLines},
+		{processor: CountLines{}, want: StreamBuffered},
+		{processor: Upper{}, want: StreamChunked},
+		// without a streaming configuration the whole input is needed
+		{processor: Reverse{}, want: StreamBuffered},
+		{processor: FormatJSON{}, want: StreamBuffered},
+		{processor: JSONToYAML{}, want: StreamBuffered},
+		{processor: ShuffleLines{}, want: StreamBuffered},
+		{processor: Bcrypt{}, want: StreamBuffered},
+		{processor: HexDecode{}, want: StreamBuffered},
+		{processor: DefaultStreamingProcessor{}, want: StreamBuffered},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%T", tt.processor), func(t *testing.T) {
+			if got := StreamingModeOf(tt.processor); got != tt.want {
+				t.Errorf("StreamingModeOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestStreamingModeOf_Chunked makes sure every chunked processor gives the
+// same output when its input is split into chunks
+func TestStreamingModeOf_Chunked(t *testing.T) {
+	text := []byte(strings.Repeat("Hello, wörld! 世界 ", 20000))
+	for _, p := range All() {
+		if StreamingModeOf(p) != StreamChunked {
+			continue
+		}
+		t.Run(p.Name(), func(t *testing.T) {
+			input := text
+			want, err := p.Transform(input)
+			if err != nil {
+				t.Fatalf("Transform() error = %v", err)
+			}
+
+			var writer bytes.Buffer
+			if err := TransformStream(p, &chunkReader{data: input, chunkSize: 4093}, &writer); err != nil {
+				t.Fatalf("TransformStream() error = %v", err)
+			}
+			if writer.String() != want {
+				t.Errorf("TransformStream() differs from Transform() on a %d bytes input", len(input))
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ func (p Lower) GetStreamingConfig() StreamingConfig {
 		ChunkSize:    64 * 1024, // 64KB chunks
 		BufferOutput: false,     // Can process chunks directly
 		LineByLine:   false,
+		RuneAligned:  true, // Never split multi-byte characters
 	}
 }
 
@@ -50,6 +51,10 @@ func (p Lower) Description() string {
 	return "Transform your text to lower case"
 }
 
+func (p Lower) Category() string {
+	return CategoryCase
+}
+
 func (p Lower) FilterValue() string {
 	return p.Title()
 }
@@ -64,6 +69,7 @@ func (p Upper) GetStreamingConfig() StreamingConfig {
 		ChunkSize:    64 * 1024, // 64KB chunks
 		BufferOutput: false,     // Can process chunks directly
 		LineByLine:   false,
+		RuneAligned:  true, // Never split multi-byte characters
 	}
 }
 
@@ -92,6 +98,10 @@ func (p Upper) Description() string {
 	return "Transform your text to UPPER CASE"
 }
 
+func (p Upper) Category() string {
+	return CategoryCase
+}
+
 func (p Upper) FilterValue() string {
 	return p.Title()
 }
@@ -125,6 +135,10 @@ func (p Title) Description() string {
 	return "Transform your text to Title Case"
 }
 
+func (p Title) Category() string {
+	return CategoryCase
+}
+
 func (p Title) FilterValue() string {
 	return p.Title()
 }
@@ -159,6 +173,14 @@ func (p Snake) Description() string {
 	return "Transform your text to snake_case"
 }
 
+func (p Snake) Category() string {
+	return CategoryCase
+}
+
+func (p Snake) Tags() []string {
+	return []string{"underscore"}
+}
+
 func (p Snake) FilterValue() string {
 	return p.Title()
 }
@@ -192,6 +214,14 @@ func (p Kebab) Description() string {
 	return "Transform your text to kebab-case"
 }
 
+func (p Kebab) Category() string {
+	return CategoryCase
+}
+
+func (p Kebab) Tags() []string {
+	return []string{"dash", "hyphen"}
+}
+
 func (p Kebab) FilterValue() string {
 	return p.Title()
 }
@@ -226,6 +256,10 @@ func (p Camel) Description() string {
 	return "Transform your text to camelCase"
 }
 
+func (p Camel) Category() string {
+	return CategoryCase
+}
+
 func (p Camel) FilterValue() string {
 	return p.Title()
 }
@@ -260,6 +294,10 @@ func (p Pascal) Description() string {
 	return "Transform your text to PascalCase"
 }
 
+func (p Pascal) Category() string {
+	return CategoryCase
+}
+
 func (p Pascal) FilterValue() string {
 	return p.Title()
 }
@@ -294,6 +332,14 @@ func (p Slug) Description() string {
 	return "Transform your text to slug-case"
 }
 
+func (p Slug) Category() string {
+	return CategoryCase
+}
+
+func (p Slug) Tags() []string {
+	return []string{"url"}
+}
+
 func (p Slug) FilterValue() string {
 	return p.Title()
 }
@@ -431,27 +477,22 @@ func (p EscapeQuotes) Alias() []string {
 }
 
 func (p EscapeQuotes) Transform(data []byte, f ...Flag) (string, error) {
+	flags, err := ParseFlags(p.Flags(), f...)
+	if err != nil {
+		return "", err
+	}
+	double := flags.Bool("double-quote")
+	single := flags.Bool("single-quote")
+	// passing only some of the flags escapes only those quotes
+	if flags.IsSet("double-quote") || flags.IsSet("single-quote") {
+		double = double && flags.IsSet("double-quote")
+		single = single && flags.IsSet("single-quote")
+	}
+
 	var result strings.Builder
 	for _, v := range data {
-		for _, flag := range f {
-			switch flag.Short {
-			case "d":
-				if v == '"' {
-					result.WriteString("\\")
-				}
-			case "s":
-				if v == '\'' {
-					result.WriteString("\\")
-				}
-			}
-		}
-		if len(f) == 0 {
-			if v == '"' {
-				result.WriteString("\\")
-			}
-			if v == '\'' {
-				result.WriteString("\\")
-			}
+		if (double && v == '"') || (single && v == '\'') {
+			result.WriteString("\\")
 		}
 		result.WriteString(string(v))
 	}
@@ -486,6 +527,10 @@ func (p EscapeQuotes) Description() string {
 	return "Escapes single and double quotes by default"
 }
 
+func (p EscapeQuotes) Category() string {
+	return CategoryEncoding
+}
+
 func (p EscapeQuotes) FilterValue() string {
 	return p.Title()
 }
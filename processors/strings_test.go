@@ -1064,6 +1064,21 @@ func TestEscapeQuotes(t *testing.T) {
 			args: args{data: []byte("this is 'great' \"test\""), f: []Flag{{Short: "d", Value: true}, {Short: "s", Value: true}}},
 			want: "this is \\'great\\' \\\"test\\\"",
 		},
+		{
+			name: "Only double quote flag",
+			args: args{data: []byte("this is 'great' \"test\""), f: []Flag{{Short: "d", Value: true}}},
+			want: "this is 'great' \\\"test\\\"",
+		},
+		{
+			name: "Only single quote flag",
+			args: args{data: []byte("this is 'great' \"test\""), f: []Flag{{Short: "s", Value: true}}},
+			want: "this is \\'great\\' \"test\"",
+		},
+		{
+			name: "Single quote turned off",
+			args: args{data: []byte("this is 'great' \"test\""), f: []Flag{{Short: "d", Value: true}, {Short: "s", Value: false}}},
+			want: "this is 'great' \\\"test\\\"",
+		},
 		{
 			name: "String with no quote",
 			args: args{data: []byte("this is great test")},
@@ -35,6 +35,14 @@ func (p URLEncode) Description() string {
 	return "Encode URL entities"
 }
 
+func (p URLEncode) Category() string {
+	return CategoryEncoding
+}
+
+func (p URLEncode) Tags() []string {
+	return []string{"percent-encoding", "query"}
+}
+
 func (p URLEncode) FilterValue() string {
 	return p.Title()
 }
@@ -68,6 +76,14 @@ func (p URLDecode) Description() string {
 	return "Decode URL entities"
 }
 
+func (p URLDecode) Category() string {
+	return CategoryEncoding
+}
+
+func (p URLDecode) Tags() []string {
+	return []string{"percent-encoding", "query"}
+}
+
 func (p URLDecode) FilterValue() string {
 	return p.Title()
 }
@@ -111,6 +127,14 @@ func (p ExtractURLs) Description() string {
 	return "Extract URLs from text"
 }
 
+func (p ExtractURLs) Category() string {
+	return CategoryExtraction
+}
+
+func (p ExtractURLs) Tags() []string {
+	return []string{"find", "grep", "links"}
+}
+
 func (p ExtractURLs) FilterValue() string {
 	return p.Title()
 }
@@ -2,13 +2,33 @@ package processors
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/harsh16coder/xxhash"
+	"github.com/zeebo/xxh3"
 )
 
 // XXH64 encodes string to XXH64
 type XXH64 struct{}
 
+// Implement StreamingProcessor interface
+func (x XXH64) CanStream() bool {
+	return true
+}
+
+func (x XXH64) PreferStream() bool {
+	return true
+}
+
+func (x XXH64) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
+	return hashStream(xxhash.New64(), reader, writer)
+}
+
+// Implement DigestProcessor interface
+func (x XXH64) IsDigest() bool {
+	return true
+}
+
 func (x XXH64) Name() string {
 	return "xxh-64"
 }
@@ -39,6 +59,14 @@ func (x XXH64) Description() string {
 	return "Get the XXH64 checksum of your text"
 }
 
+func (x XXH64) Category() string {
+	return CategoryHashing
+}
+
+func (x XXH64) Tags() []string {
+	return []string{"checksum", "xxhash"}
+}
+
 func (x XXH64) FilterValue() string {
 	return x.Title()
 }
@@ -46,6 +74,24 @@ func (x XXH64) FilterValue() string {
 // XX32 encodes string to XXH32
 type XXH32 struct{}
 
+// Implement StreamingProcessor interface
+func (x XXH32) CanStream() bool {
+	return true
+}
+
+func (x XXH32) PreferStream() bool {
+	return true
+}
+
+func (x XXH32) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
+	return hashStream(xxhash.New32(), reader, writer)
+}
+
+// Implement DigestProcessor interface
+func (x XXH32) IsDigest() bool {
+	return true
+}
+
 func (x XXH32) Name() string {
 	return "xxh-32"
 }
@@ -76,6 +122,14 @@ func (x XXH32) Description() string {
 	return "Get the XXH32 checksum of your text"
 }
 
+func (x XXH32) Category() string {
+	return CategoryHashing
+}
+
+func (x XXH32) Tags() []string {
+	return []string{"checksum", "xxhash"}
+}
+
 func (x XXH32) FilterValue() string {
 	return x.Title()
 }
@@ -83,6 +137,24 @@ func (x XXH32) FilterValue() string {
 // XX128 encodes string to XXH32
 type XXH128 struct{}
 
+// Implement StreamingProcessor interface
+func (x XXH128) CanStream() bool {
+	return true
+}
+
+func (x XXH128) PreferStream() bool {
+	return true
+}
+
+func (x XXH128) TransformStream(reader io.Reader, writer io.Writer, _ ...Flag) error {
+	return hashStream(newXXH128(), reader, writer)
+}
+
+// Implement DigestProcessor interface
+func (x XXH128) IsDigest() bool {
+	return true
+}
+
 func (x XXH128) Name() string {
 	return "xxh-128"
 }
@@ -92,12 +164,7 @@ func (x XXH128) Alias() []string {
 }
 
 func (x XXH128) Transform(data []byte, _ ...Flag) (string, error) {
-	h := xxhash.New128()
-	if _, err := h.Write(data); err != nil {
-		return "", err
-	}
-	s := h.Sum128()
-	return fmt.Sprintf("%016x%016x", s.Hi, s.Lo), nil
+	return hashHex(newXXH128(), data), nil
 }
 
 func (x XXH128) Flags() []Flag {
@@ -113,6 +180,33 @@ func (x XXH128) Description() string {
 	return "Get the XXH128 checksum of your text"
 }
 
+func (x XXH128) Category() string {
+	return CategoryHashing
+}
+
+func (x XXH128) Tags() []string {
+	return []string{"checksum", "xxhash"}
+}
+
 func (x XXH128) FilterValue() string {
 	return x.Title()
 }
+
+// xxh128Hash adapts the incremental xxh3 hasher to a 128 bit hash.Hash,
+// the digest is the big endian Hi half followed by the Lo half
+type xxh128Hash struct {
+	*xxh3.Hasher
+}
+
+func newXXH128() xxh128Hash {
+	return xxh128Hash{xxh3.New()}
+}
+
+func (h xxh128Hash) Size() int {
+	return 16
+}
+
+func (h xxh128Hash) Sum(b []byte) []byte {
+	sum := h.Sum128().Bytes()
+	return append(b, sum[:]...)
+}
@@ -0,0 +1,410 @@
+// Package rpc serves the sttr processors over newline-delimited JSON-RPC 2.0,
+// one message per line, so editors can keep a single sttr process running.
+//
+// Methods:
+//
+//	list                    every processor, like GET /v1/processors
+//	describe  {processor}   a single processor
+//	transform {processor, flags, input, encoding, chain}
+//	                        run a processor and/or a chain on the input
+//
+// Requests are handled concurrently and answered in the order they finish.
+// The $/cancelRequest notification, {"id": <request id>}, cancels a pending
+// request which is then answered with the RequestCancelled error. Ids are
+// compared as JSON values, so 1 and 1.0 name the same request, and a request
+// reusing the id of a pending one is answered with InvalidRequest.
+//
+// ServeHandler runs the same connection with other methods, e.g. sttr mcp.
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/abhimanyu003/sttr/config"
+	"github.com/abhimanyu003/sttr/processors"
+)
+
+// DefaultMaxMessageSize is the default of Options.MaxMessageSize
+const DefaultMaxMessageSize = 64 << 20 // 64MB
+
+// error codes, the negative ones are defined by JSON-RPC 2.0
+const (
+	CodeParseError       = -32700
+	CodeInvalidRequest   = -32600
+	CodeMethodNotFound   = -32601
+	CodeInvalidParams    = -32602
+	CodeInternalError    = -32603
+	CodeRequestCancelled = -32800
+	// CodeTransformFailed is returned when the processor fails on the input
+	CodeTransformFailed = 1
+)
+
+// Options configure Serve, the zero value uses the defaults
+type Options struct {
+	// Config gives the default flag values and the aliases usable in chains
+	Config *config.Config
+	// MaxMessageSize is the longest line accepted, in bytes
+	MaxMessageSize int
+}
+
+// TransformParams are the params of the transform method. Chain is a
+// chain in the syntax of sttr pipe run on the output of Processor, either
+// can be omitted.
+type TransformParams struct {
+	Processor string         `json:"processor,omitempty"`
+	Flags     map[string]any `json:"flags,omitempty"`
+	Input     string         `json:"input"`
+	// Encoding of Input, utf-8 (default) or base64
+	Encoding string `json:"encoding,omitempty"`
+	Chain    string `json:"chain,omitempty"`
+}
+
+// TransformResult is the result of the transform method
+type TransformResult struct {
+	Result string `json:"result"`
+	// Encoding of Result, utf-8 or base64 when it is not text
+	Encoding string `json:"encoding"`
+	// Command is the command line equivalent to the transformation
+	Command string `json:"command"`
+}
+
+// DescribeParams are the params of the describe method
+type DescribeParams struct {
+	Processor string `json:"processor"`
+}
+
+// CancelParams are the params of the $/cancelRequest notification, or
+// of notifications/cancelled which names the request RequestID
+type CancelParams struct {
+	ID        json.RawMessage `json:"id,omitempty"`
+	RequestID json.RawMessage `json:"requestId,omitempty"`
+}
+
+// Error is the error of a failed request, Data.Kind is usage, input or
+// transform like the sttr command line reports
+type Error struct {
+	Code    int        `json:"code"`
+	Message string     `json:"message"`
+	Data    *ErrorData `json:"data,omitempty"`
+}
+
+// ErrorData gives the kind of error of the failed requests
+type ErrorData struct {
+	Kind string `json:"kind"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// NewError returns an error with the code, kind is set as Data when not empty
+func NewError(code int, kind string, err error) *Error {
+	e := &Error{Code: code, Message: err.Error()}
+	if kind != "" {
+		e.Data = &ErrorData{Kind: kind}
+	}
+	return e
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type resultResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result"`
+}
+
+type errorResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Error   *Error          `json:"error"`
+}
+
+// Handler answers a request, the result of notifications is dropped.
+// The context is cancelled when the request is.
+type Handler func(ctx context.Context, method string, params json.RawMessage) (any, *Error)
+
+type conn struct {
+	handle Handler
+
+	mu  sync.Mutex
+	out *json.Encoder
+
+	pendingMu sync.Mutex
+	pending   map[string]context.CancelFunc
+}
+
+// Serve answers the requests read from in on out until in ends, then
+// waits for the pending requests. Cancelling ctx cancels them.
+func Serve(ctx context.Context, in io.Reader, out io.Writer, opts Options) error {
+	m := &methods{opts: opts}
+	return ServeHandler(ctx, in, out, opts.MaxMessageSize, m.handle)
+}
+
+// ServeHandler is Serve with the requests answered by handle, lines longer
+// than maxMessageSize bytes are an error, 0 is DefaultMaxMessageSize.
+// Besides $/cancelRequest, the notifications/cancelled notification of the
+// Model Context Protocol, {"requestId": <request id>}, cancels a request.
+func ServeHandler(ctx context.Context, in io.Reader, out io.Writer, maxMessageSize int, handle Handler) error {
+	if maxMessageSize <= 0 {
+		maxMessageSize = DefaultMaxMessageSize
+	}
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	c := &conn{handle: handle, out: enc, pending: make(map[string]context.CancelFunc)}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			c.reply(nil, nil, NewError(CodeParseError, "", fmt.Errorf("parse error: %w", err)))
+			continue
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			c.reply(req.ID, nil, NewError(CodeInvalidRequest, "", errors.New(`invalid request: jsonrpc must be "2.0" and method set`)))
+			continue
+		}
+		if req.Method == "$/cancelRequest" || req.Method == "notifications/cancelled" {
+			c.cancel(req.Params)
+			continue
+		}
+
+		reqCtx, reqCancel := context.WithCancel(ctx)
+		key, err := c.track(req.ID, reqCancel)
+		if err != nil {
+			reqCancel()
+			c.reply(req.ID, nil, err)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer reqCancel()
+
+			result, err := c.handle(reqCtx, req.Method, req.Params)
+			// the id can be used again once the client has the response
+			c.untrack(key)
+			if req.ID == nil {
+				// notifications are never answered
+				return
+			}
+			c.reply(req.ID, result, err)
+		}()
+	}
+	return scanner.Err()
+}
+
+// methods are the methods of sttr rpc
+type methods struct {
+	opts Options
+}
+
+func (m *methods) handle(ctx context.Context, method string, raw json.RawMessage) (any, *Error) {
+	switch method {
+	case "list":
+		infos := make([]processors.Info, 0)
+		for _, p := range processors.All() {
+			infos = append(infos, processors.InfoOf(p))
+		}
+		return infos, nil
+	case "describe":
+		var params DescribeParams
+		if err := DecodeParams(raw, &params); err != nil {
+			return nil, err
+		}
+		p, ok := processors.Lookup(params.Processor)
+		if !ok {
+			return nil, NewError(CodeInvalidParams, "usage", fmt.Errorf("unknown processor %s", params.Processor))
+		}
+		return processors.InfoOf(p), nil
+	case "transform":
+		var params TransformParams
+		if err := DecodeParams(raw, &params); err != nil {
+			return nil, err
+		}
+		return m.transform(ctx, params)
+	default:
+		return nil, NewError(CodeMethodNotFound, "", fmt.Errorf("method not found: %s", method))
+	}
+}
+
+// transform runs the processor and chain of params on its input
+func (m *methods) transform(ctx context.Context, params TransformParams) (*TransformResult, *Error) {
+	req := processors.Request{Input: params.Input, Encoding: params.Encoding, Defaults: m.opts.Config.Defaults}
+	if params.Processor != "" {
+		p, ok := processors.Lookup(params.Processor)
+		if !ok {
+			return nil, NewError(CodeInvalidParams, "usage", fmt.Errorf("unknown processor %s", params.Processor))
+		}
+		flags, err := processors.FlagsFromMap(p.Flags(), params.Flags)
+		if err != nil {
+			return nil, NewError(CodeInvalidParams, "usage", err)
+		}
+		req.Processor, req.Flags = p, flags
+	} else if len(params.Flags) > 0 {
+		return nil, NewError(CodeInvalidParams, "usage", errors.New("flags need a processor, chain steps take their own flags"))
+	}
+	if params.Chain != "" {
+		steps, err := m.opts.Config.ParseChain(params.Chain)
+		if err != nil {
+			return nil, NewError(CodeInvalidParams, "usage", err)
+		}
+		req.Chain = steps
+	}
+
+	res, err := processors.Run(ctx, req)
+	if err != nil {
+		return nil, runError(err)
+	}
+	return &TransformResult{Result: res.Result, Encoding: res.Encoding, Command: res.Chain.CommandLine()}, nil
+}
+
+// runError returns the error answering a request failed with the error of
+// processors.Run
+func runError(err error) *Error {
+	var reqErr *processors.RequestError
+	switch {
+	case errors.Is(err, context.Canceled):
+		return NewError(CodeRequestCancelled, "", errors.New("request cancelled"))
+	case errors.As(err, &reqErr) && reqErr.Kind == "transform":
+		return NewError(CodeTransformFailed, "transform", err)
+	case errors.As(err, &reqErr):
+		return NewError(CodeInvalidParams, reqErr.Kind, err)
+	default:
+		return NewError(CodeInternalError, "", err)
+	}
+}
+
+// DecodeParams unmarshals the params of a request into v
+func DecodeParams(raw json.RawMessage, v any) *Error {
+	if len(raw) == 0 {
+		return NewError(CodeInvalidParams, "usage", errors.New("params are required"))
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return NewError(CodeInvalidParams, "usage", fmt.Errorf("invalid params: %w", err))
+	}
+	return nil
+}
+
+func (c *conn) reply(id json.RawMessage, result any, err *Error) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	var resp any = resultResponse{JSONRPC: "2.0", ID: id, Result: result}
+	if err != nil {
+		resp = errorResponse{JSONRPC: "2.0", ID: id, Error: err}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.out.Encode(resp)
+}
+
+// track records the cancel function of the request id under its key, an
+// id already used by a pending request is an error. Notifications and
+// requests with a null id can't be cancelled and have no key.
+func (c *conn) track(id json.RawMessage, cancel context.CancelFunc) (string, *Error) {
+	if id == nil {
+		return "", nil
+	}
+	key, ok := IDKey(id)
+	if !ok {
+		return "", NewError(CodeInvalidRequest, "", errors.New("invalid request: id must be a string, a number or null"))
+	}
+	if key == "" {
+		return "", nil
+	}
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+	if _, ok := c.pending[key]; ok {
+		return "", NewError(CodeInvalidRequest, "", fmt.Errorf("invalid request: id %s is already used by a pending request", id))
+	}
+	c.pending[key] = cancel
+	return key, nil
+}
+
+func (c *conn) untrack(key string) {
+	if key == "" {
+		return
+	}
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+	delete(c.pending, key)
+}
+
+// cancel cancels the request named by the params of $/cancelRequest or
+// notifications/cancelled, unknown and finished requests are ignored
+func (c *conn) cancel(raw json.RawMessage) {
+	var params CancelParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return
+	}
+	id := params.ID
+	if id == nil {
+		id = params.RequestID
+	}
+	key, ok := IDKey(id)
+	if !ok || key == "" {
+		return
+	}
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+	if cancel, ok := c.pending[key]; ok {
+		cancel()
+	}
+}
+
+// IDKey returns the key of a request id, ids which are the same JSON value
+// have the same key, e.g. 1 and 1.0, and null has the empty key. ok is
+// false when id is not a string, a number or null.
+func IDKey(id json.RawMessage) (key string, ok bool) {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(id))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return "", false
+	}
+	switch v := v.(type) {
+	case nil:
+		return "", true
+	case string:
+		return "string:" + v, true
+	case json.Number:
+		// the precision is bounded so huge exponents are cheap to parse
+		f, _, err := big.ParseFloat(v.String(), 10, 256, big.ToNearestEven)
+		if err != nil {
+			return "", false
+		}
+		if f.Sign() == 0 {
+			// -0 is 0
+			f.SetInt64(0)
+		}
+		return "number:" + f.Text('g', -1), true
+	default:
+		return "", false
+	}
+}
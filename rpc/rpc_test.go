@@ -0,0 +1,261 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/abhimanyu003/sttr/config"
+	"github.com/abhimanyu003/sttr/processors"
+)
+
+func TestServe(t *testing.T) {
+	c, err := config.Parse([]byte("defaults:\n  zeropad.n: 1\naliases:\n  jwtbody: \"base64url-decode --raw,json\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		request string
+		want    string
+	}{
+		{
+			name:    "Transform",
+			request: `{"jsonrpc":"2.0","id":1,"method":"transform","params":{"processor":"upper","input":"hello"}}`,
+			want:    `{"jsonrpc":"2.0","id":1,"result":{"result":"HELLO","encoding":"utf-8","command":"sttr upper"}}`,
+		},
+		{
+			name:    "Flags and configured defaults",
+			request: `{"jsonrpc":"2.0","id":"a","method":"transform","params":{"processor":"zeropad","flags":{"p":"#"},"input":"7"}}`,
+			want:    `{"jsonrpc":"2.0","id":"a","result":{"result":"#07","encoding":"utf-8","command":"sttr zeropad -n 1 -p '#'"}}`,
+		},
+		{
+			name:    "Chain with an alias",
+			request: `{"jsonrpc":"2.0","id":2,"method":"transform","params":{"chain":"jwtbody,upper","input":"eyJhIjoxfQ"}}`,
+			want:    `{"jsonrpc":"2.0","id":2,"result":{"result":"{\"A\":1}","encoding":"utf-8","command":"sttr base64url-decode -r | sttr json | sttr upper"}}`,
+		},
+		{
+			name:    "Processor then chain",
+			request: `{"jsonrpc":"2.0","id":3,"method":"transform","params":{"processor":"base64-decode","chain":"upper","input":"aGk="}}`,
+			want:    `{"jsonrpc":"2.0","id":3,"result":{"result":"HI","encoding":"utf-8","command":"sttr base64-decode | sttr upper"}}`,
+		},
+		{
+			name:    "Binary input",
+			request: `{"jsonrpc":"2.0","id":4,"method":"transform","params":{"processor":"hex-encode","input":"AP8=","encoding":"base64"}}`,
+			want:    `{"jsonrpc":"2.0","id":4,"result":{"result":"00ff","encoding":"utf-8","command":"sttr hex-encode"}}`,
+		},
+		{
+			name:    "Binary output",
+			request: `{"jsonrpc":"2.0","id":4,"method":"transform","params":{"processor":"hex-decode","input":"00ff"}}`,
+			want:    `{"jsonrpc":"2.0","id":4,"result":{"result":"AP8=","encoding":"base64","command":"sttr hex-decode"}}`,
+		},
+		{
+			name:    "Output with NUL bytes",
+			request: `{"jsonrpc":"2.0","id":4,"method":"transform","params":{"processor":"base64-decode","input":"AAE="}}`,
+			want:    `{"jsonrpc":"2.0","id":4,"result":{"result":"AAE=","encoding":"base64","command":"sttr base64-decode"}}`,
+		},
+		{
+			name:    "Describe",
+			request: `{"jsonrpc":"2.0","id":5,"method":"describe","params":{"processor":"b64-dec"}}`,
+			want:    `{"jsonrpc":"2.0","id":5,"result":{"name":"base64-decode","aliases":["b64-dec","b64-decode"],"title":"Base64 Decode (base64-decode)","description":"Decode your Base64 text","category":"encoding","tags":[],"streaming":"native","prefer_stream":true,"binary":true,"digest":false,"flags":[{"name":"raw","short":"r","type":"bool","description":"unpadded base64 encoding","default":false}]}}`,
+		},
+		{
+			name:    "Unknown processor",
+			request: `{"jsonrpc":"2.0","id":6,"method":"describe","params":{"processor":"nope"}}`,
+			want:    `{"jsonrpc":"2.0","id":6,"error":{"code":-32602,"message":"unknown processor nope","data":{"kind":"usage"}}}`,
+		},
+		{
+			name:    "Unknown flag",
+			request: `{"jsonrpc":"2.0","id":7,"method":"transform","params":{"processor":"upper","flags":{"x":1},"input":"a"}}`,
+			want:    `{"jsonrpc":"2.0","id":7,"error":{"code":-32602,"message":"unknown flag x","data":{"kind":"usage"}}}`,
+		},
+		{
+			name:    "Nothing to run",
+			request: `{"jsonrpc":"2.0","id":8,"method":"transform","params":{"input":"a"}}`,
+			want:    `{"jsonrpc":"2.0","id":8,"error":{"code":-32602,"message":"processor or chain is required","data":{"kind":"usage"}}}`,
+		},
+		{
+			name:    "Transform error",
+			request: `{"jsonrpc":"2.0","id":9,"method":"transform","params":{"processor":"json","input":"x"}}`,
+			want:    `{"jsonrpc":"2.0","id":9,"error":{"code":1,"message":"invalid character 'x' looking for beginning of value","data":{"kind":"transform"}}}`,
+		},
+		{
+			name:    "Unknown method",
+			request: `{"jsonrpc":"2.0","id":10,"method":"nope"}`,
+			want:    `{"jsonrpc":"2.0","id":10,"error":{"code":-32601,"message":"method not found: nope"}}`,
+		},
+		{
+			name:    "Invalid request",
+			request: `{"id":11,"method":"list"}`,
+			want:    `{"jsonrpc":"2.0","id":11,"error":{"code":-32600,"message":"invalid request: jsonrpc must be \"2.0\" and method set"}}`,
+		},
+		{
+			name:    "Parse error",
+			request: `{"jsonrpc":`,
+			want:    `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error: unexpected end of JSON input"}}`,
+		},
+		{
+			name:    "Notification",
+			request: `{"jsonrpc":"2.0","method":"transform","params":{"processor":"upper","input":"a"}}`,
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			if err := Serve(context.Background(), strings.NewReader(tt.request+"\n"), &out, Options{Config: c}); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSuffix(out.String(), "\n"); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServe_List(t *testing.T) {
+	var out strings.Builder
+	in := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"list"}` + "\n")
+	if err := Serve(context.Background(), in, &out, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp struct {
+		Result []processors.Info `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(out.String()), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Result) < len(processors.List) {
+		t.Errorf("list returned %d processors, want at least %d", len(resp.Result), len(processors.List))
+	}
+}
+
+// blockingProcessor doesn't return until release is closed
+type blockingProcessor struct{}
+
+var release chan struct{}
+
+func (p blockingProcessor) Name() string             { return "test-rpc-block" }
+func (p blockingProcessor) Alias() []string          { return nil }
+func (p blockingProcessor) Flags() []processors.Flag { return nil }
+func (p blockingProcessor) Transform(data []byte, _ ...processors.Flag) (string, error) {
+	<-release
+	return string(data), nil
+}
+
+func TestServe_ConcurrentAndCancel(t *testing.T) {
+	if _, ok := processors.Lookup("test-rpc-block"); !ok {
+		if err := processors.Register(blockingProcessor{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	release = make(chan struct{})
+	defer close(release)
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(context.Background(), inR, outW, Options{})
+		outW.Close()
+	}()
+	dec := json.NewDecoder(outR)
+	next := func() map[string]any {
+		t.Helper()
+		var resp map[string]any
+		if err := dec.Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	io.WriteString(inW, `{"jsonrpc":"2.0","id":1,"method":"transform","params":{"processor":"test-rpc-block","input":"a"}}`+"\n")
+	io.WriteString(inW, `{"jsonrpc":"2.0","id":2,"method":"transform","params":{"processor":"upper","input":"b"}}`+"\n")
+	if resp := next(); resp["id"] != float64(2) {
+		t.Fatalf("first response = %v, want the one of request 2 while request 1 is blocked", resp)
+	}
+
+	io.WriteString(inW, `{"jsonrpc":"2.0","method":"$/cancelRequest","params":{"id":1}}`+"\n")
+	resp := next()
+	errObj, _ := resp["error"].(map[string]any)
+	if resp["id"] != float64(1) || errObj == nil || errObj["code"] != float64(CodeRequestCancelled) {
+		t.Fatalf("response after $/cancelRequest = %v, want a RequestCancelled error", resp)
+	}
+
+	// ids are compared as JSON values, 3.0 is the id of the pending request 3
+	io.WriteString(inW, `{"jsonrpc":"2.0","id":3,"method":"transform","params":{"processor":"test-rpc-block","input":"a"}}`+"\n")
+	io.WriteString(inW, `{"jsonrpc":"2.0","id":3.0,"method":"transform","params":{"processor":"upper","input":"b"}}`+"\n")
+	resp = next()
+	errObj, _ = resp["error"].(map[string]any)
+	if errObj == nil || errObj["code"] != float64(CodeInvalidRequest) {
+		t.Fatalf("response to a duplicate id = %v, want an InvalidRequest error", resp)
+	}
+	io.WriteString(inW, `{"jsonrpc":"2.0","id":{"a":1},"method":"transform","params":{"processor":"upper","input":"b"}}`+"\n")
+	resp = next()
+	errObj, _ = resp["error"].(map[string]any)
+	if errObj == nil || errObj["code"] != float64(CodeInvalidRequest) {
+		t.Fatalf("response to an object id = %v, want an InvalidRequest error", resp)
+	}
+	io.WriteString(inW, `{"jsonrpc":"2.0","method":"$/cancelRequest","params":{"id":3e0}}`+"\n")
+	resp = next()
+	errObj, _ = resp["error"].(map[string]any)
+	if resp["id"] != float64(3) || errObj == nil || errObj["code"] != float64(CodeRequestCancelled) {
+		t.Fatalf("response after $/cancelRequest of 3e0 = %v, want request 3 cancelled", resp)
+	}
+	// the id is free again once its request is answered
+	io.WriteString(inW, `{"jsonrpc":"2.0","id":3,"method":"transform","params":{"processor":"upper","input":"b"}}`+"\n")
+	if resp = next(); resp["id"] != float64(3) || resp["error"] != nil {
+		t.Fatalf("response = %v, want the result of request 3", resp)
+	}
+
+	// the cancellation of the Model Context Protocol names the request requestId
+	io.WriteString(inW, `{"jsonrpc":"2.0","id":"b","method":"transform","params":{"processor":"test-rpc-block","input":"a"}}`+"\n")
+	io.WriteString(inW, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"b"}}`+"\n")
+	resp = next()
+	errObj, _ = resp["error"].(map[string]any)
+	if resp["id"] != "b" || errObj == nil || errObj["code"] != float64(CodeRequestCancelled) {
+		t.Fatalf("response after notifications/cancelled = %v, want a RequestCancelled error", resp)
+	}
+
+	inW.Close()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIDKey(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{a: `1`, b: `1.0`, equal: true},
+		{a: `1`, b: `1e0`, equal: true},
+		{a: `-0`, b: `0`, equal: true},
+		{a: `"a"`, b: ` "a" `, equal: true},
+		{a: `"a"`, b: `"a"`, equal: true},
+		{a: `1`, b: `"1"`},
+		{a: `1`, b: `2`},
+		{a: `9007199254740993`, b: `9007199254740992`},
+	}
+	for _, tt := range tests {
+		a, okA := IDKey(json.RawMessage(tt.a))
+		b, okB := IDKey(json.RawMessage(tt.b))
+		if !okA || !okB || (a == b) != tt.equal {
+			t.Errorf("IDKey(%s) = %q, IDKey(%s) = %q, want equal %v", tt.a, a, tt.b, b, tt.equal)
+		}
+	}
+
+	for _, id := range []string{`{}`, `[1]`, `true`, `1e999999999999`} {
+		if key, ok := IDKey(json.RawMessage(id)); ok {
+			t.Errorf("IDKey(%s) = %q, want an invalid id", id, key)
+		}
+	}
+	if key, ok := IDKey(json.RawMessage(`null`)); !ok || key != "" {
+		t.Errorf("IDKey(null) = %q, %v, want the empty key", key, ok)
+	}
+}
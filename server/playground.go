@@ -0,0 +1,27 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// playgroundFiles is the browser UI served at /, a single page using the API
+//
+//go:embed playground
+var playgroundFiles embed.FS
+
+// playground serves the browser UI, everything it loads comes from the
+// server so it works offline
+func playground() http.Handler {
+	files, err := fs.Sub(playgroundFiles, "playground")
+	if err != nil {
+		panic(err)
+	}
+	fileServer := http.FileServerFS(files)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "default-src 'self'")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		fileServer.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,331 @@
+// The playground mirrors the interactive UI of sttr: pick a processor,
+// tune its flags and the output is refreshed as the input changes.
+"use strict";
+
+const $ = (id) => document.getElementById(id);
+
+const state = {
+  processors: [],
+  visible: [],
+  selected: null,
+  // flag values per processor, kept while switching between them
+  flags: {},
+  // bytes of an opened file which isn't valid UTF-8
+  binaryInput: null,
+  output: null,
+  request: null,
+  timer: 0,
+};
+
+const categoryTitle = (c) => c.charAt(0).toUpperCase() + c.slice(1).replace(/-/g, " ");
+
+async function load() {
+  const resp = await fetch("v1/processors");
+  if (!resp.ok) {
+    setStatus(`failed to load the processors: ${resp.status} ${resp.statusText}`);
+    return;
+  }
+  state.processors = await resp.json();
+
+  const categories = [...new Set(state.processors.map((p) => p.category))];
+  for (const c of categories.sort()) {
+    $("category").append(new Option(categoryTitle(c), c));
+  }
+
+  const initial = decodeURIComponent(location.hash.slice(1));
+  renderList();
+  select(state.processors.find((p) => p.name === initial || p.aliases.includes(initial)) || state.visible[0]);
+}
+
+// matches filters like sttr list: a substring of the name, title, aliases or tags
+function matches(p, query, category) {
+  if (category && p.category !== category) {
+    return false;
+  }
+  if (!query) {
+    return true;
+  }
+  const terms = [p.name, p.title, categoryTitle(p.category), ...p.aliases, ...p.tags];
+  return terms.some((t) => t.toLowerCase().includes(query));
+}
+
+function renderList() {
+  const query = $("search").value.trim().toLowerCase();
+  const category = $("category").value;
+  state.visible = state.processors.filter((p) => matches(p, query, category));
+
+  const list = $("processors");
+  list.replaceChildren(...state.visible.map((p) => {
+    const li = document.createElement("li");
+    li.role = "option";
+    li.dataset.name = p.name;
+    li.title = p.aliases.length ? `aliases: ${p.aliases.join(", ")}` : p.name;
+    li.setAttribute("aria-selected", String(p === state.selected));
+    const desc = document.createElement("span");
+    desc.className = "desc";
+    desc.textContent = p.description;
+    li.append(p.title, desc);
+    li.addEventListener("click", () => select(p));
+    return li;
+  }));
+  if (!state.visible.length) {
+    const li = document.createElement("li");
+    li.textContent = "No processor matches";
+    list.append(li);
+  }
+}
+
+function select(p) {
+  if (!p) {
+    return;
+  }
+  state.selected = p;
+  history.replaceState(null, "", `#${p.name}`);
+  for (const li of $("processors").children) {
+    const on = li.dataset.name === p.name;
+    li.setAttribute("aria-selected", String(on));
+    if (on) {
+      li.scrollIntoView({ block: "nearest" });
+    }
+  }
+  renderFlags();
+  refresh();
+}
+
+// move selects the processor delta rows away in the visible list
+function move(delta) {
+  const i = state.visible.indexOf(state.selected);
+  const next = Math.min(Math.max(i + delta, 0), state.visible.length - 1);
+  select(state.visible[next]);
+}
+
+function flagValues(p) {
+  if (!state.flags[p.name]) {
+    state.flags[p.name] = Object.fromEntries(p.flags.map((f) => [f.name, defaultValue(f)]));
+  }
+  return state.flags[p.name];
+}
+
+function defaultValue(f) {
+  if (f.default !== undefined) {
+    return f.default;
+  }
+  if (f.choices) {
+    return f.choices[0];
+  }
+  switch (f.type) {
+    case "bool": return false;
+    case "int": case "uint": return 0;
+    default: return "";
+  }
+}
+
+// renderFlags builds a control per flag of the selected processor
+function renderFlags() {
+  const p = state.selected;
+  const values = flagValues(p);
+  const form = $("flags");
+  form.replaceChildren();
+  $("flags-pane").hidden = p.flags.length === 0;
+
+  for (const f of p.flags) {
+    const label = document.createElement("label");
+    label.title = f.description;
+    let control;
+    if (f.type === "bool") {
+      control = document.createElement("input");
+      control.type = "checkbox";
+      control.checked = Boolean(values[f.name]);
+      control.addEventListener("change", () => { values[f.name] = control.checked; refresh(); });
+      label.append(control, f.name);
+      form.append(label);
+      continue;
+    }
+
+    if (f.choices) {
+      control = document.createElement("select");
+      control.append(...f.choices.map((c) => new Option(c, c)));
+    } else {
+      control = document.createElement("input");
+      control.type = f.type === "string" ? "text" : "number";
+      if (f.type !== "string") {
+        control.step = "1";
+        control.min = f.range ? f.range.min : f.type === "uint" ? 0 : "";
+        control.max = f.range ? f.range.max : "";
+      }
+    }
+    control.value = values[f.name];
+    control.addEventListener("input", () => {
+      if (control.type === "number" && !control.validity.valid) {
+        setStatus(`--${f.name}: ${control.validationMessage}`);
+        return;
+      }
+      values[f.name] = control.type === "number" ? Number(control.value) : control.value;
+      refresh();
+    });
+    label.append(f.name, control);
+    form.append(label);
+  }
+}
+
+// commandLine shows the command equivalent to the selection, like the
+// output title of the interactive UI
+function commandLine(p, values) {
+  const quote = (s) => /^[\w\-.,:/=+@%]+$/.test(s) ? s : `'${s.replace(/'/g, "'\\''")}'`;
+  const args = ["sttr", p.name];
+  for (const f of p.flags) {
+    const value = values[f.name];
+    if (String(value) === String(defaultValue(f))) {
+      continue;
+    }
+    const name = f.short ? `-${f.short}` : `--${f.name}`;
+    if (f.type === "bool") {
+      args.push(value ? name : `--${f.name}=false`);
+    } else {
+      args.push(name, quote(String(value)));
+    }
+  }
+  return args.join(" ");
+}
+
+// refresh runs the selected processor once the input stops changing
+function refresh() {
+  clearTimeout(state.timer);
+  state.timer = setTimeout(transform, 150);
+}
+
+async function transform() {
+  const p = state.selected;
+  if (!p) {
+    return;
+  }
+  const values = flagValues(p);
+  $("command").textContent = `Output · $ ${commandLine(p, values)}`;
+
+  state.request?.abort();
+  const request = new AbortController();
+  state.request = request;
+
+  // only the changed flags are sent so the configured defaults still apply
+  const flags = Object.fromEntries(p.flags
+    .filter((f) => String(values[f.name]) !== String(defaultValue(f)))
+    .map((f) => [f.name, values[f.name]]));
+  const body = { input: $("input").value, flags };
+  if (state.binaryInput !== null) {
+    body.input = state.binaryInput;
+    body.encoding = "base64";
+  }
+
+  let resp, data;
+  try {
+    resp = await fetch(`v1/${encodeURIComponent(p.name)}`, {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify(body),
+      signal: request.signal,
+    });
+    data = await resp.json();
+  } catch (err) {
+    if (err.name !== "AbortError") {
+      showError(err.message);
+    }
+    return;
+  }
+
+  if (!resp.ok) {
+    showError(data.error ? `${data.error.kind} error: ${data.error.message}` : resp.statusText);
+    return;
+  }
+  state.output = data;
+  const out = $("output");
+  out.classList.remove("error");
+  out.textContent = data.result;
+  setStatus(data.encoding === "base64"
+    ? `binary output, ${atob(data.result).length} bytes shown base64 encoded`
+    : "");
+}
+
+function showError(message) {
+  state.output = null;
+  $("output").classList.add("error");
+  $("output").textContent = message;
+  setStatus("");
+}
+
+function setStatus(message) {
+  $("status").textContent = message;
+}
+
+// openFile loads a file as the input, binary files are sent base64 encoded
+async function openFile(file) {
+  const bytes = new Uint8Array(await file.arrayBuffer());
+  try {
+    $("input").value = new TextDecoder("utf-8", { fatal: true }).decode(bytes);
+    state.binaryInput = null;
+  } catch {
+    let bin = "";
+    for (let i = 0; i < bytes.length; i += 0x8000) {
+      bin += String.fromCharCode(...bytes.subarray(i, i + 0x8000));
+    }
+    state.binaryInput = btoa(bin);
+    $("input").value = `[${file.name}: ${bytes.length} bytes of binary data, type to replace]`;
+  }
+  refresh();
+}
+
+$("input").addEventListener("input", () => {
+  state.binaryInput = null;
+  refresh();
+});
+$("file").addEventListener("change", (e) => e.target.files[0] && openFile(e.target.files[0]));
+$("search").addEventListener("input", () => {
+  renderList();
+  if (!state.visible.includes(state.selected)) {
+    select(state.visible[0]);
+  }
+});
+$("category").addEventListener("change", () => {
+  renderList();
+  if (!state.visible.includes(state.selected)) {
+    select(state.visible[0]);
+  }
+});
+$("search").addEventListener("keydown", (e) => {
+  switch (e.key) {
+    case "ArrowDown": move(1); e.preventDefault(); break;
+    case "ArrowUp": move(-1); e.preventDefault(); break;
+    case "Enter": $("input").focus(); break;
+  }
+});
+document.addEventListener("keydown", (e) => {
+  const typing = ["INPUT", "TEXTAREA", "SELECT"].includes(document.activeElement.tagName);
+  if (e.key === "/" && !typing) {
+    $("search").focus();
+    e.preventDefault();
+  } else if (e.key === "Escape") {
+    $("search").focus();
+  }
+});
+$("copy").addEventListener("click", async () => {
+  if (!state.output) {
+    setStatus("nothing to copy, the output is an error");
+    return;
+  }
+  await navigator.clipboard.writeText(state.output.result);
+  setStatus("output copied to the clipboard");
+});
+$("use").addEventListener("click", () => {
+  if (!state.output) {
+    return;
+  }
+  if (state.output.encoding === "base64") {
+    state.binaryInput = state.output.result;
+    $("input").value = `[${atob(state.output.result).length} bytes of binary data, type to replace]`;
+  } else {
+    state.binaryInput = null;
+    $("input").value = state.output.result;
+  }
+  refresh();
+});
+
+load();
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServer_Playground(t *testing.T) {
+	h := New(Options{})
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{path: "/", wantStatus: http.StatusOK, wantType: "text/html; charset=utf-8", wantBody: "<title>sttr playground</title>"},
+		{path: "/app.js", wantStatus: http.StatusOK, wantType: "text/javascript; charset=utf-8", wantBody: `fetch("v1/processors")`},
+		{path: "/style.css", wantStatus: http.StatusOK, wantType: "text/css; charset=utf-8"},
+		{path: "/nope.js", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantType != "" && rec.Header().Get("Content-Type") != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", rec.Header().Get("Content-Type"), tt.wantType)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body doesn't contain %q", tt.wantBody)
+			}
+			if tt.wantStatus == http.StatusOK && rec.Header().Get("Content-Security-Policy") != "default-src 'self'" {
+				t.Errorf("Content-Security-Policy = %q", rec.Header().Get("Content-Security-Policy"))
+			}
+		})
+	}
+}
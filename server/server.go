@@ -0,0 +1,329 @@
+// Package server exposes the sttr processors as an HTTP API:
+//
+//	GET  /                        the playground, a browser UI
+//	GET  /healthz                 liveness probe
+//	GET  /v1/processors           every processor with its flags
+//	GET  /v1/processors/{name}    a single processor
+//	POST /v1/{processor}          run a processor on the request body
+//
+// The flags of POST requests are query parameters, e.g.
+// POST /v1/json?indent=true, and the body is the input. With the
+// Content-Type application/json the body is a Request and the response a
+// Response instead, so binary data can be exchanged base64 encoded.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/abhimanyu003/sttr/config"
+	"github.com/abhimanyu003/sttr/processors"
+)
+
+// defaults of Options
+const (
+	DefaultMaxBodySize = 10 << 20 // 10MB
+	DefaultTimeout     = 30 * time.Second
+)
+
+// Options configure the server, the zero value uses the defaults
+type Options struct {
+	// MaxBodySize is the largest request body accepted, in bytes
+	MaxBodySize int64
+	// Timeout bounds the time spent on a transformation
+	Timeout time.Duration
+	// MaxTransforms bounds the transformations running at once, timed out
+	// ones included since they can't be stopped. 0 is the number of CPUs.
+	MaxTransforms int
+	// Config gives the default flag values of the processors
+	Config *config.Config
+	// Log receives the errors of requests which failed while the
+	// response was written, nil discards them
+	Log *log.Logger
+}
+
+// Request is the body of a POST request sent as application/json
+type Request struct {
+	Input string `json:"input"`
+	// Encoding of Input, utf-8 (default) or base64
+	Encoding string         `json:"encoding,omitempty"`
+	Flags    map[string]any `json:"flags,omitempty"`
+}
+
+// Response is the body of the response to a Request
+type Response struct {
+	Processor string         `json:"processor"`
+	Flags     map[string]any `json:"flags,omitempty"`
+	Result    string         `json:"result"`
+	// Encoding of Result, utf-8 or base64 when it is not text
+	Encoding string `json:"encoding"`
+}
+
+// ErrorResponse is the body of the responses of failed requests,
+// Kind is usage, input or transform like the sttr command line reports
+type ErrorResponse struct {
+	Error struct {
+		Kind    string `json:"kind"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type server struct {
+	opts Options
+	// slots holds a value for every running transformation
+	slots chan struct{}
+}
+
+// New returns the handler of the API
+func New(opts Options) http.Handler {
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = DefaultMaxBodySize
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxTransforms <= 0 {
+		opts.MaxTransforms = runtime.NumCPU()
+	}
+	s := &server{opts: opts, slots: make(chan struct{}, opts.MaxTransforms)}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", playground())
+	mux.HandleFunc("GET /healthz", s.health)
+	mux.HandleFunc("GET /v1/processors", s.list)
+	mux.HandleFunc("GET /v1/processors/{name}", s.describe)
+	mux.HandleFunc("POST /v1/{processor}", s.transform)
+	return mux
+}
+
+func (s *server) health(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, "ok\n")
+}
+
+func (s *server) list(w http.ResponseWriter, r *http.Request) {
+	infos := make([]processors.Info, 0)
+	for _, p := range processors.All() {
+		infos = append(infos, processors.InfoOf(p))
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+func (s *server) describe(w http.ResponseWriter, r *http.Request) {
+	p, ok := processors.Lookup(r.PathValue("name"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "usage", fmt.Errorf("unknown processor %s", r.PathValue("name")))
+		return
+	}
+	writeJSON(w, http.StatusOK, processors.InfoOf(p))
+}
+
+func (s *server) transform(w http.ResponseWriter, r *http.Request) {
+	p, ok := processors.Lookup(r.PathValue("processor"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "usage", fmt.Errorf("unknown processor %s", r.PathValue("processor")))
+		return
+	}
+
+	query := make(map[string]any)
+	for key, values := range r.URL.Query() {
+		query[key] = values[len(values)-1]
+	}
+	flags, err := processors.FlagsFromMap(p.Flags(), query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "usage", err)
+		return
+	}
+	req := processors.Request{Processor: p, Flags: flags, Defaults: s.opts.Config.Defaults, Slots: s.slots}
+
+	if r.ContentLength > s.opts.MaxBodySize {
+		// streamed output may be sent before the limit is reached
+		writeReadError(w, &http.MaxBytesError{Limit: s.opts.MaxBodySize})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.opts.Timeout)
+	defer cancel()
+	body := http.MaxBytesReader(w, r.Body, s.opts.MaxBodySize)
+
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "application/json" {
+		s.transformJSON(ctx, w, req, body)
+		return
+	}
+	if processors.PreferStream(p) {
+		s.transformStream(ctx, w, p, req.Steps()[0].Flags, body)
+		return
+	}
+
+	in, err := io.ReadAll(body)
+	if err != nil {
+		writeReadError(w, err)
+		return
+	}
+	req.Input = string(in)
+	res, err := run(ctx, req)
+	if err != nil {
+		writeTransformError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentType(res.Output))
+	w.Write(res.Output)
+}
+
+// transformJSON runs the processor of req on the input of a Request and
+// writes a Response
+func (s *server) transformJSON(ctx context.Context, w http.ResponseWriter, req processors.Request, body io.Reader) {
+	var jsonReq Request
+	if err := json.NewDecoder(body).Decode(&jsonReq); err != nil {
+		writeReadError(w, err)
+		return
+	}
+	bodyFlags, err := processors.FlagsFromMap(req.Processor.Flags(), jsonReq.Flags)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "usage", err)
+		return
+	}
+	req.Flags = append(req.Flags, bodyFlags...)
+	req.Input, req.Encoding = jsonReq.Input, jsonReq.Encoding
+
+	res, err := run(ctx, req)
+	if err != nil {
+		writeTransformError(w, err)
+		return
+	}
+
+	resp := Response{Processor: req.Processor.Name(), Result: res.Result, Encoding: res.Encoding}
+	if values, err := processors.ParseFlags(req.Processor.Flags(), res.Chain[0].Flags...); err == nil && len(req.Processor.Flags()) > 0 {
+		resp.Flags = values.Map()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// transformStream streams the body through p. Errors before the first byte
+// of output get an error response, later ones abort the response so the
+// client doesn't take a truncated result for a complete one.
+func (s *server) transformStream(ctx context.Context, w http.ResponseWriter, p processors.Processor, flags []processors.Flag, body io.Reader) {
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		writeTransformError(w, errTimeout)
+		return
+	}
+
+	// slow clients are bounded by the deadlines, a running Transform can't be stopped
+	deadline, _ := ctx.Deadline()
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(deadline)
+	_ = rc.SetWriteDeadline(deadline)
+
+	sw := &startWriter{w: w, binary: processors.IsBinary(p)}
+	err := processors.TransformStream(p, body, sw, flags...)
+	switch {
+	case err == nil:
+		if !sw.started {
+			w.Header().Set("Content-Type", contentType(nil))
+			w.WriteHeader(http.StatusOK)
+		}
+	case !sw.started:
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeReadError(w, err)
+			return
+		}
+		writeTransformError(w, err)
+	default:
+		if s.opts.Log != nil {
+			s.opts.Log.Printf("%s: %v", p.Name(), err)
+		}
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// startWriter sets the Content-Type on the first write
+type startWriter struct {
+	w       http.ResponseWriter
+	binary  bool
+	started bool
+}
+
+func (sw *startWriter) Write(b []byte) (int, error) {
+	if !sw.started {
+		sw.started = true
+		ct := contentType(b)
+		if sw.binary {
+			ct = "application/octet-stream"
+		}
+		sw.w.Header().Set("Content-Type", ct)
+	}
+	return sw.w.Write(b)
+}
+
+// errTimeout is returned by run when the transformation took too long
+var errTimeout = errors.New("the transformation timed out")
+
+// run runs req with processors.Run, errTimeout is returned when ctx is done
+// first because of its deadline
+func run(ctx context.Context, req processors.Request) (*processors.Result, error) {
+	res, err := processors.Run(ctx, req)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, errTimeout
+	}
+	return res, err
+}
+
+func contentType(out []byte) string {
+	if processors.IsText(out) {
+		return "text/plain; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, kind string, err error) {
+	var resp ErrorResponse
+	resp.Error.Kind = kind
+	resp.Error.Message = err.Error()
+	writeJSON(w, status, resp)
+}
+
+// writeReadError reports an error reading the request body
+func writeReadError(w http.ResponseWriter, err error) {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		writeError(w, http.StatusRequestEntityTooLarge, "input", fmt.Errorf("the body is larger than %d bytes", maxErr.Limit))
+		return
+	}
+	writeError(w, http.StatusBadRequest, "input", err)
+}
+
+// writeTransformError reports an error of the processor
+func writeTransformError(w http.ResponseWriter, err error) {
+	var reqErr *processors.RequestError
+	switch {
+	case errors.As(err, &reqErr) && reqErr.Kind != "transform":
+		// the input of the request can't be decoded
+		writeError(w, http.StatusBadRequest, reqErr.Kind, err)
+	case errors.Is(err, errTimeout):
+		writeError(w, http.StatusGatewayTimeout, "transform", err)
+	case errors.Is(err, context.Canceled):
+		// the client is gone
+	default:
+		writeError(w, http.StatusUnprocessableEntity, "transform", err)
+	}
+}
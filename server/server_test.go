@@ -0,0 +1,243 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abhimanyu003/sttr/config"
+	"github.com/abhimanyu003/sttr/processors"
+)
+
+func TestServer_Transform(t *testing.T) {
+	srv := httptest.NewServer(New(Options{}))
+	defer srv.Close()
+
+	tests := []struct {
+		name        string
+		path        string
+		body        string
+		contentType string
+		wantStatus  int
+		wantBody    string
+		wantType    string
+	}{
+		{name: "Buffered", path: "/v1/upper", body: "hello", wantStatus: http.StatusOK, wantBody: "HELLO", wantType: "text/plain; charset=utf-8"},
+		{name: "Alias", path: "/v1/b64-enc", body: "hello", wantStatus: http.StatusOK, wantBody: "aGVsbG8="},
+		{name: "Streamed digest", path: "/v1/md5", body: "hello", wantStatus: http.StatusOK, wantBody: "5d41402abc4b2a76b9719d911017c592"},
+		{name: "Query flags", path: "/v1/zeropad?n=2&p=x", body: "7", wantStatus: http.StatusOK, wantBody: "x007"},
+		{name: "Bool flag without a value", path: "/v1/json?indent", body: `{"a":1}`, wantStatus: http.StatusOK, wantBody: "{\n  \"a\": 1\n}"},
+		{name: "Binary output", path: "/v1/hex-decode", body: "00ff", wantStatus: http.StatusOK, wantBody: "\x00\xff", wantType: "application/octet-stream"},
+		{name: "Unknown processor", path: "/v1/nope", body: "x", wantStatus: http.StatusNotFound, wantBody: `{"error":{"kind":"usage","message":"unknown processor nope"}}`},
+		{name: "Unknown flag", path: "/v1/upper?x=1", body: "x", wantStatus: http.StatusBadRequest, wantBody: `{"error":{"kind":"usage","message":"unknown flag x"}}`},
+		{name: "Transform error", path: "/v1/json", body: "nope", wantStatus: http.StatusUnprocessableEntity, wantType: "application/json"},
+		{
+			name:        "JSON request",
+			path:        "/v1/zeropad?n=1",
+			body:        `{"input": "7", "flags": {"prefix": "#"}}`,
+			contentType: "application/json",
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"processor":"zeropad","flags":{"number-of-zeros":1,"prefix":"#"},"result":"#07","encoding":"utf-8"}`,
+		},
+		{
+			name:        "JSON request with binary input and output",
+			path:        "/v1/hex-encode",
+			body:        `{"input": "AP8=", "encoding": "base64"}`,
+			contentType: "application/json; charset=utf-8",
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"processor":"hex-encode","result":"00ff","encoding":"utf-8"}`,
+		},
+		{
+			name:        "JSON request with binary output",
+			path:        "/v1/base64-decode",
+			body:        `{"input": "AP8="}`,
+			contentType: "application/json",
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"processor":"base64-decode","flags":{"raw":false},"result":"AP8=","encoding":"base64"}`,
+		},
+		{
+			name:        "JSON request with NUL bytes in the output",
+			path:        "/v1/base64-decode",
+			body:        `{"input": "AAE="}`,
+			contentType: "application/json",
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"processor":"base64-decode","flags":{"raw":false},"result":"AAE=","encoding":"base64"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := tt.contentType
+			if ct == "" {
+				ct = "text/plain"
+			}
+			resp, err := http.Post(srv.URL+tt.path, ct, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%s)", resp.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantBody != "" && strings.TrimSuffix(string(body), "\n") != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+			if tt.wantType != "" && resp.Header.Get("Content-Type") != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", resp.Header.Get("Content-Type"), tt.wantType)
+			}
+		})
+	}
+}
+
+func TestServer_MaxBodySize(t *testing.T) {
+	srv := httptest.NewServer(New(Options{MaxBodySize: 4}))
+	defer srv.Close()
+
+	for _, path := range []string{"/v1/upper", "/v1/json", "/v1/md5"} {
+		resp, err := http.Post(srv.URL+path, "text/plain", strings.NewReader("too large"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusRequestEntityTooLarge {
+			t.Errorf("%s: status = %d, want %d", path, resp.StatusCode, http.StatusRequestEntityTooLarge)
+		}
+	}
+}
+
+// slowProcessor takes longer than the timeout of the test
+type slowProcessor struct{}
+
+func (p slowProcessor) Name() string             { return "test-slow" }
+func (p slowProcessor) Alias() []string          { return nil }
+func (p slowProcessor) Flags() []processors.Flag { return nil }
+func (p slowProcessor) Transform(data []byte, _ ...processors.Flag) (string, error) {
+	time.Sleep(200 * time.Millisecond)
+	return string(data), nil
+}
+
+func TestServer_Timeout(t *testing.T) {
+	if err := processors.Register(slowProcessor{}); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(New(Options{Timeout: 10 * time.Millisecond}))
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/v1/test-slow", "text/plain", strings.NewReader("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusGatewayTimeout)
+	}
+}
+
+// blockingProcessor doesn't return until release is closed
+type blockingProcessor struct{}
+
+var release chan struct{}
+
+func (p blockingProcessor) Name() string             { return "test-block" }
+func (p blockingProcessor) Alias() []string          { return nil }
+func (p blockingProcessor) Flags() []processors.Flag { return nil }
+func (p blockingProcessor) Transform(data []byte, _ ...processors.Flag) (string, error) {
+	<-release
+	return string(data), nil
+}
+
+func TestServer_MaxTransforms(t *testing.T) {
+	if _, ok := processors.Lookup("test-block"); !ok {
+		if err := processors.Register(blockingProcessor{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	release = make(chan struct{})
+	srv := httptest.NewServer(New(Options{Timeout: 50 * time.Millisecond, MaxTransforms: 1}))
+	defer srv.Close()
+	post := func(path string) int {
+		resp, err := http.Post(srv.URL+path, "text/plain", strings.NewReader("x"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := post("/v1/test-block"); status != http.StatusGatewayTimeout {
+		t.Fatalf("status = %d, want %d", status, http.StatusGatewayTimeout)
+	}
+	// the timed out transformation keeps running and holds the only slot
+	for _, path := range []string{"/v1/upper", "/v1/md5"} {
+		if status := post(path); status != http.StatusGatewayTimeout {
+			t.Errorf("%s: status = %d while the slot is held, want %d", path, status, http.StatusGatewayTimeout)
+		}
+	}
+
+	close(release)
+	deadline := time.Now().Add(5 * time.Second)
+	for post("/v1/upper") != http.StatusOK {
+		if time.Now().After(deadline) {
+			t.Fatal("the slot wasn't released after the transformation returned")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status := post("/v1/md5"); status != http.StatusOK {
+		t.Errorf("/v1/md5: status = %d, want %d", status, http.StatusOK)
+	}
+}
+
+func TestServer_ConfigDefaults(t *testing.T) {
+	c, err := config.Parse([]byte("defaults:\n  zeropad.n: 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := New(Options{Config: c})
+
+	for path, want := range map[string]string{"/v1/zeropad": "07", "/v1/zeropad?n=3": "0007"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader("7")))
+		if rec.Body.String() != want {
+			t.Errorf("POST %s = %q, want %q", path, rec.Body.String(), want)
+		}
+	}
+}
+
+func TestServer_Catalogue(t *testing.T) {
+	h := New(Options{})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/processors", nil))
+	var infos []processors.Info
+	if err := json.Unmarshal(rec.Body.Bytes(), &infos); err != nil {
+		t.Fatalf("GET /v1/processors: %v", err)
+	}
+	if len(infos) < len(processors.List) {
+		t.Errorf("GET /v1/processors returned %d processors, want at least %d", len(infos), len(processors.List))
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/processors/b64-dec", nil))
+	var info processors.Info
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil || info.Name != "base64-decode" || len(info.Flags) != 1 {
+		t.Errorf("GET /v1/processors/b64-dec = %+v, %v", info, err)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/processors/nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET /v1/processors/nope status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok\n" {
+		t.Errorf("GET /healthz = %d %q", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/abhimanyu003/sttr/processors"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	fieldStyle        = lipgloss.NewStyle().PaddingLeft(2)
+	focusedFieldStyle = lipgloss.NewStyle().
+				PaddingLeft(1).
+				BorderStyle(lipgloss.NormalBorder()).
+				BorderLeft(true).
+				BorderForeground(borderStyle)
+	flagNameStyle = lipgloss.NewStyle().Bold(true)
+	flagDescStyle = lipgloss.NewStyle().Foreground(mutedStyle)
+)
+
+// form edits the flags of a processor, one field is generated for every
+// flag returned by Flags(): bool flags are toggled, flags with Choices are
+// cycled and the other flags are typed in
+type form struct {
+	processor processors.Processor
+	fields    []field
+	cursor    int
+	err       error
+}
+
+type field struct {
+	flag   processors.Flag
+	text   textinput.Model
+	on     bool
+	choice int
+}
+
+// newForm returns a form for p filled with the current flag values,
+// flags missing from current get their default
+func newForm(p processors.Processor, current []processors.Flag) *form {
+	f := &form{processor: p}
+	for _, def := range p.Flags() {
+		value := def.Value
+		for _, c := range current {
+			if c.Name == def.Name {
+				value = c.Value
+			}
+		}
+
+		fd := field{flag: def}
+		switch {
+		case def.Type == processors.FlagBool:
+			fd.on, _ = value.(bool)
+		case len(def.Choices) > 0:
+			for i, c := range def.Choices {
+				if c == fmt.Sprint(value) {
+					fd.choice = i
+				}
+			}
+		default:
+			fd.text = textinput.New()
+			fd.text.Prompt = ""
+			if value != nil {
+				fd.text.SetValue(fmt.Sprint(value))
+			}
+		}
+		f.fields = append(f.fields, fd)
+	}
+	f.focus(0)
+	return f
+}
+
+// focus moves the cursor to field i
+func (f *form) focus(i int) {
+	if len(f.fields) == 0 {
+		return
+	}
+	f.fields[f.cursor].text.Blur()
+	f.cursor = (i + len(f.fields)) % len(f.fields)
+	if fd := &f.fields[f.cursor]; fd.typed() {
+		fd.text.Focus()
+	}
+}
+
+// typed reports whether the field value is typed in a text input
+func (fd field) typed() bool {
+	return fd.flag.Type != processors.FlagBool && len(fd.flag.Choices) == 0
+}
+
+// Update handles the keys editing the focused field
+func (f *form) Update(msg tea.KeyMsg) tea.Cmd {
+	if len(f.fields) == 0 {
+		return nil
+	}
+
+	switch msg.String() {
+	case "up", "shift+tab":
+		f.focus(f.cursor - 1)
+		return nil
+	case "down", "tab":
+		f.focus(f.cursor + 1)
+		return nil
+	}
+
+	fd := &f.fields[f.cursor]
+	var cmd tea.Cmd
+	switch {
+	case fd.flag.Type == processors.FlagBool:
+		switch msg.String() {
+		case " ", "left", "right", "h", "l":
+			fd.on = !fd.on
+		}
+	case len(fd.flag.Choices) > 0:
+		switch msg.String() {
+		case "left", "h":
+			fd.choice = (fd.choice - 1 + len(fd.flag.Choices)) % len(fd.flag.Choices)
+		case "right", "l", " ":
+			fd.choice = (fd.choice + 1) % len(fd.flag.Choices)
+		}
+	default:
+		fd.text, cmd = fd.text.Update(msg)
+	}
+
+	_, f.err = f.Flags()
+	return cmd
+}
+
+// Flags returns the values of the form converted to the flag types,
+// it fails when a value doesn't pass the processor flag validation
+func (f *form) Flags() ([]processors.Flag, error) {
+	opts := make([]processors.Flag, 0, len(f.fields))
+	for _, fd := range f.fields {
+		opt := processors.Flag{Name: fd.flag.Name, Short: fd.flag.Short}
+		switch {
+		case fd.flag.Type == processors.FlagBool:
+			opt.Value = fd.on
+		case len(fd.flag.Choices) > 0:
+			opt.Value = fd.flag.Choices[fd.choice]
+		default:
+			opt.Value = fd.text.Value()
+		}
+		opts = append(opts, opt)
+	}
+
+	values, err := processors.ParseFlags(f.processor.Flags(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make([]processors.Flag, 0, len(opts))
+	for _, def := range f.processor.Flags() {
+		switch def.Type {
+		case processors.FlagBool:
+			def.Value = values.Bool(def.Name)
+		case processors.FlagInt:
+			def.Value = values.Int(def.Name)
+		case processors.FlagUint:
+			def.Value = values.Uint(def.Name)
+		default:
+			def.Value = values.String(def.Name)
+		}
+		flags = append(flags, def)
+	}
+	return flags, nil
+}
+
+func (f *form) View(width, height int) string {
+	var b strings.Builder
+	b.WriteString(paneTitleStyle.Render("Flags · "+f.processor.Name()) + "\n\n")
+
+	if len(f.fields) == 0 {
+		b.WriteString(flagDescStyle.Render("  This processor has no flags"))
+	}
+
+	for i, fd := range f.fields {
+		var value string
+		switch {
+		case fd.flag.Type == processors.FlagBool:
+			value = "[ ]"
+			if fd.on {
+				value = "[x]"
+			}
+		case len(fd.flag.Choices) > 0:
+			value = "‹ " + fd.flag.Choices[fd.choice] + " ›"
+		default:
+			fd.text.Width = max(width-6, 1)
+			value = fd.text.View()
+		}
+
+		row := flagNameStyle.Render("--"+fd.flag.Name) + " " +
+			flagDescStyle.Render(strings.ToLower(fd.flag.Type.String())+", default "+strconv.Quote(fmt.Sprint(defaultValue(fd.flag)))) + "\n" +
+			flagDescStyle.Render(fd.flag.Desc) + "\n" +
+			value
+
+		style := fieldStyle
+		if i == f.cursor {
+			style = focusedFieldStyle
+		}
+		b.WriteString(style.Width(width).Render(row) + "\n\n")
+	}
+
+	if f.err != nil {
+		b.WriteString(lipgloss.NewStyle().Width(width).Foreground(errorStyle).Render(f.err.Error()))
+	}
+
+	return lipgloss.NewStyle().Width(width).MaxHeight(height).Render(b.String())
+}
+
+// defaultValue returns the default of a flag, nil defaults are shown
+// as the zero value of the flag type
+func defaultValue(flag processors.Flag) any {
+	if flag.Value != nil {
+		return flag.Value
+	}
+	switch flag.Type {
+	case processors.FlagBool:
+		return false
+	case processors.FlagInt, processors.FlagUint:
+		return 0
+	}
+	return ""
+}
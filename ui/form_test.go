@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/abhimanyu003/sttr/processors"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// formProcessor has a flag of every type
+type formProcessor struct{}
+
+func (p formProcessor) Name() string    { return "test-form" }
+func (p formProcessor) Alias() []string { return nil }
+func (p formProcessor) Transform(data []byte, _ ...processors.Flag) (string, error) {
+	return string(data), nil
+}
+
+func (p formProcessor) Flags() []processors.Flag {
+	return []processors.Flag{
+		{Name: "indent", Short: "i", Type: processors.FlagBool},
+		{Name: "count", Short: "c", Type: processors.FlagInt, Value: 1, Range: &processors.FlagRange{Min: -5, Max: 5}},
+		{Name: "width", Short: "w", Type: processors.FlagUint, Value: uint(8), Range: &processors.FlagRange{Min: 1, Max: 80}},
+		{Name: "prefix", Short: "p", Type: processors.FlagString, Value: "x"},
+		{Name: "mode", Short: "m", Type: processors.FlagString, Value: "a", Choices: []string{"a", "b"}},
+	}
+}
+
+func TestForm_Flags(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []processors.Flag
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name: "Defaults",
+			want: map[string]any{"indent": false, "count": 1, "width": uint(8), "prefix": "x", "mode": "a"},
+		},
+		{
+			name: "Valid values",
+			current: []processors.Flag{
+				{Name: "indent", Value: true},
+				{Name: "count", Value: "-5"},
+				{Name: "width", Value: " 80 "},
+				{Name: "prefix", Value: ""},
+				{Name: "mode", Value: "b"},
+			},
+			want: map[string]any{"indent": true, "count": -5, "width": uint(80), "prefix": "", "mode": "b"},
+		},
+		{
+			// bools are toggled and choices cycled, other values can't be picked
+			name:    "Bool and choice out of the form",
+			current: []processors.Flag{{Name: "indent", Value: "yes"}, {Name: "mode", Value: "c"}},
+			want:    map[string]any{"indent": false, "count": 1, "width": uint(8), "prefix": "x", "mode": "a"},
+		},
+		{name: "Int not a number", current: []processors.Flag{{Name: "count", Value: "x"}}, wantErr: `flag --count: invalid number "x"`},
+		{name: "Int empty", current: []processors.Flag{{Name: "count", Value: ""}}, wantErr: `flag --count: invalid number ""`},
+		{name: "Int fraction", current: []processors.Flag{{Name: "count", Value: "1.5"}}, wantErr: `flag --count: invalid number "1.5"`},
+		{name: "Int overflow", current: []processors.Flag{{Name: "count", Value: "99999999999999999999"}}, wantErr: "flag --count: invalid number"},
+		{name: "Int over the range", current: []processors.Flag{{Name: "count", Value: "6"}}, wantErr: "flag --count: 6 is out of range, must be between -5 and 5"},
+		{name: "Int under the range", current: []processors.Flag{{Name: "count", Value: "-6"}}, wantErr: "flag --count: -6 is out of range, must be between -5 and 5"},
+		{name: "Uint not a number", current: []processors.Flag{{Name: "width", Value: "wide"}}, wantErr: `flag --width: invalid number "wide"`},
+		{name: "Uint negative", current: []processors.Flag{{Name: "width", Value: "-1"}}, wantErr: "flag --width: -1 must not be negative"},
+		{name: "Uint under the range", current: []processors.Flag{{Name: "width", Value: "0"}}, wantErr: "flag --width: 0 is out of range, must be between 1 and 80"},
+		{name: "Uint over the range", current: []processors.Flag{{Name: "width", Value: "81"}}, wantErr: "flag --width: 81 is out of range, must be between 1 and 80"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, err := newForm(formProcessor{}, tt.current).Flags()
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("Flags() error = %v, want %q", err, tt.wantErr)
+				}
+				if flags != nil {
+					t.Errorf("Flags() = %v with an error, want nil", flags)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Flags() error = %v", err)
+			}
+			got := make(map[string]any)
+			for _, f := range flags {
+				got[f.Name] = f.Value
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Flags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForm_UpdateShowsInvalidInput(t *testing.T) {
+	f := newForm(formProcessor{}, nil)
+	f.Update(tea.KeyMsg{Type: tea.KeyDown}) // focus --count
+
+	f.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if f.err == nil || !strings.Contains(f.err.Error(), `invalid number "1x"`) {
+		t.Errorf("err = %v after typing a letter in --count, want an invalid number", f.err)
+	}
+	f.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if f.err != nil {
+		t.Errorf("err = %v after fixing --count, want nil", f.err)
+	}
+}
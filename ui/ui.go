@@ -1,73 +1,169 @@
 package ui
 
 import (
+	"encoding/hex"
 	"fmt"
 	"log"
 	"os"
+	"slices"
+	"strings"
 
 	"golang.org/x/term"
 
+	"github.com/abhimanyu003/sttr/config"
 	"github.com/abhimanyu003/sttr/processors"
-
 	"github.com/abhimanyu003/sttr/utils"
+
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 var (
 	maxWidth     = 72
-	appStyle     = lipgloss.NewStyle().MaxWidth(maxWidth)
 	borderStyle  = lipgloss.AdaptiveColor{Light: "#874BFD", Dark: "#7D56F4"}
 	specialStyle = lipgloss.AdaptiveColor{Light: "#43BF6D", Dark: "#73F59F"}
+	mutedStyle   = lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}
+	errorStyle   = lipgloss.AdaptiveColor{Light: "#E3342F", Dark: "#FF5F87"}
+
+	paneStyle = lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(mutedStyle)
+	focusedPaneStyle = paneStyle.BorderForeground(borderStyle)
+	paneTitleStyle   = lipgloss.NewStyle().Bold(true).Foreground(specialStyle)
+	helpStyle        = lipgloss.NewStyle().Foreground(mutedStyle)
+)
+
+// focus is the pane receiving key presses
+type focus int
+
+const (
+	focusList focus = iota
+	focusInput
+	focusOutput
 )
 
 type UI struct {
-	list              list.Model
-	input             string
-	quitting          bool
-	selectedProcessor processors.Processor
+	list     list.Model
+	input    textarea.Model
+	output   viewport.Model
+	focus    focus
+	quitting bool
+	width    int
+	height   int
+
+	// steps are the processors applied so far, the highlighted processor
+	// runs on their output
+	steps processors.Chain
+
+	// result is set when the user accepts the output,
+	// it is run and printed once the program exits
+	result processors.Chain
+
+	// flags holds the flag values set for each processor by name,
+	// form is the flag editor while it is open
+	flags map[string][]processors.Flag
+	form  *form
+
+	// aliases are the user aliases listed before the processors
+	aliases []list.Item
+
+	// category limits the list to the processors of a category,
+	// empty for every processor and alias
+	category string
+
+	// ranking lists the decoders which succeed on the input instead,
+	// rankedOf is the input they were detected for and rankingID
+	// discards detections started before the last change
+	ranking   bool
+	rankedOf  string
+	rankingID int
+
+	// preview is the result of the last run of the highlighted processor,
+	// previewID discards results of runs started before the last change
+	preview   preview
+	previewID int
+	previewOf string
+
+	// status is a message shown in the help line until the next key press
+	status string
+}
+
+// preview is the output of a processor for the current input
+type preview struct {
+	id  int
+	out string
+	err error
+}
+
+// ranking is the list of the decoders detected for the current input
+type ranking struct {
+	id    int
+	items []list.Item
 }
 
 func New(input string) UI {
+	in := textarea.New()
+	in.Placeholder = "Type or paste the text to transform"
+	in.ShowLineNumbers = false
+	in.CharLimit = 0
+	in.MaxHeight = 0
+	in.SetValue(input)
+
 	return UI{
-		input: input,
+		input: in,
+		flags: make(map[string][]processors.Flag),
 	}
 }
 
-func (u *UI) Render() {
-	termWidth, _, err := term.GetSize(int(os.Stdout.Fd()))
-	if err != nil || termWidth > maxWidth {
-		termWidth = maxWidth
+// SetConfig applies the user configuration: the configured defaults become
+// the initial flag values and the aliases are listed before the processors
+func (u *UI) SetConfig(c *config.Config) {
+	for _, name := range c.Processors() {
+		u.flags[name] = c.Defaults(name)
 	}
-	if u.input == "" {
-		divider := lipgloss.NewStyle().Margin(0, 1).Foreground(borderStyle).Render("•")
-		info := lipgloss.NewStyle().Foreground(specialStyle).Render("[ Enter 2 empty lines to process ]")
-
-		title := lipgloss.NewStyle().
-			MaxWidth(termWidth).
-			BorderStyle(lipgloss.NormalBorder()).
-			BorderTop(true).
-			BorderBottom(true).
-			BorderForeground(borderStyle).
-			Render("Provide string to transform" + divider + info)
-
-		fmt.Println(appStyle.Render(title))
-		u.input = utils.ReadMultilineInput()
+	u.aliases = nil
+	for _, name := range c.Aliases() {
+		chain, err := c.Alias(name)
+		if err != nil {
+			continue
+		}
+		spec, _ := c.AliasSpec(name)
+		u.aliases = append(u.aliases, aliasItem{name: name, spec: spec, chain: chain})
 	}
+}
 
-	u.list = list.New(processors.List, list.NewDefaultDelegate(), 0, 0)
-	u.list.Title = "Select transformation"
+func (u *UI) Render() {
+	u.list = list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	u.list.SetShowHelp(false)
+	u.setCategory("")
+	if u.input.Value() == "" {
+		u.focus = focusInput
+		u.input.Focus()
+	}
 
-	if _, err := tea.NewProgram(u, tea.WithAltScreen()).Run(); err != nil {
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		// stdin was used for the input, read keys from the terminal instead
+		opts = append(opts, tea.WithInputTTY())
+	}
+	if _, err := tea.NewProgram(u, opts...).Run(); err != nil {
 		log.Fatalf("error running ui: %v", err)
 	}
 
-	if u.selectedProcessor != nil {
-		data, err := u.selectedProcessor.Transform([]byte(u.input))
+	if u.result != nil {
+		termWidth, _, err := term.GetSize(int(os.Stdout.Fd()))
+		if err != nil || termWidth > maxWidth {
+			termWidth = maxWidth
+		}
+
+		data, err := u.result.Transform([]byte(u.input.Value()))
 		if err != nil {
 			data = fmt.Sprintf("error: %s", err.Error())
 		}
+		data = printable(data)
 
 		border := lipgloss.NewStyle().
 			Width(termWidth).
@@ -81,38 +177,520 @@ func (u *UI) Render() {
 }
 
 func (u *UI) Init() tea.Cmd {
-	return nil
+	return tea.Batch(textarea.Blink, u.refreshPreview())
 }
 
 func (u *UI) View() string {
 	if u.quitting {
 		return ""
 	}
+	listStyle, inputStyle, outputStyle := paneStyle, paneStyle, paneStyle
+	switch u.focus {
+	case focusList:
+		listStyle = focusedPaneStyle
+	case focusInput:
+		inputStyle = focusedPaneStyle
+	case focusOutput:
+		outputStyle = focusedPaneStyle
+	}
 
-	return appStyle.Render(u.list.View())
+	left := listStyle.Render(u.list.View())
+	if u.form != nil {
+		w, h := u.list.Width(), u.list.Height()
+		left = focusedPaneStyle.Width(w).Height(h).Render(u.form.View(w, h))
+	}
+	right := lipgloss.JoinVertical(lipgloss.Left,
+		inputStyle.Render(paneTitleStyle.Render("Input")+"\n"+u.input.View()),
+		outputStyle.Render(paneTitleStyle.MaxWidth(u.output.Width).Render(u.outputTitle())+"\n"+u.output.View()),
+	)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, left, right),
+		helpStyle.MaxWidth(u.width).Render(u.helpText()),
+	)
 }
 
 func (u *UI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
 	switch msg := msg.(type) {
+	case preview:
+		if msg.id == u.previewID {
+			u.preview = msg
+			u.setOutput()
+		}
+		return u, nil
+	case ranking:
+		if u.ranking && msg.id == u.rankingID {
+			u.list.ResetFilter()
+			u.list.SetItems(msg.items)
+			u.list.ResetSelected()
+			return u, u.refreshPreview()
+		}
+		return u, nil
+	case tea.WindowSizeMsg:
+		u.width, u.height = msg.Width, msg.Height
+		u.layout()
+		u.setOutput()
+		return u, nil
 	case tea.KeyMsg:
+		u.status = ""
+		if u.form != nil {
+			return u, u.updateForm(msg)
+		}
+
+		switch msg.String() {
+		case "ctrl+c":
+			u.quitting = true
+			return u, tea.Quit
+		case "ctrl+y":
+			u.copyOutput()
+			return u, nil
+		case "tab", "shift+tab":
+			if u.focus == focusList && u.list.FilterState() == list.Filtering {
+				break
+			}
+			step := 1
+			if msg.String() == "shift+tab" {
+				step = 2
+			}
+			u.setFocus((u.focus + focus(step)) % 3)
+			return u, nil
+		}
+
+		if u.focus != focusList {
+			if msg.String() == "esc" {
+				u.setFocus(focusList)
+				return u, nil
+			}
+			break
+		}
+
 		if u.list.FilterState() == list.Filtering {
 			break
 		}
-		switch keypress := msg.String(); keypress {
-		case "q", "ctrl+c":
+		switch msg.String() {
+		case "q", "esc":
+			if u.list.FilterState() == list.FilterApplied && msg.String() == "esc" {
+				break
+			}
 			u.quitting = true
 			return u, tea.Quit
 		case "enter":
-			u.selectedProcessor = u.list.SelectedItem().(processors.Processor)
-			u.quitting = true
-			return u, tea.Quit
+			if chain := u.chain(); len(chain) > 0 {
+				u.result = chain
+				u.quitting = true
+				return u, tea.Quit
+			}
+		case "c":
+			u.copyOutput()
+			return u, nil
+		case "[", "]":
+			u.nextCategory(msg.String() == "]")
+			return u, u.refreshPreview()
+		case "r":
+			if u.ranking {
+				u.setCategory(u.category)
+			} else {
+				u.ranking = true
+				u.list.ResetFilter()
+				u.list.Title = "Suggested decoders"
+				u.list.SetItems(nil)
+				return u, tea.Batch(u.refreshRanking(), u.refreshPreview())
+			}
+			return u, u.refreshPreview()
+		case "p":
+			if len(u.steps) > 0 {
+				u.result = u.steps
+				u.quitting = true
+				return u, tea.Quit
+			}
+		case "a":
+			if c, ok := u.list.SelectedItem().(chainItem); ok {
+				u.steps = append(u.steps, c.Chain()...)
+				return u, tea.Batch(u.refreshRanking(), u.refreshPreview())
+			}
+			if p, ok := u.highlighted(); ok {
+				u.steps = append(u.steps, processors.ChainStep{Processor: p, Flags: u.flags[p.Name()]})
+				return u, u.refreshPreview()
+			}
+		case "backspace":
+			if len(u.steps) > 0 {
+				u.steps = u.steps[:len(u.steps)-1]
+				return u, tea.Batch(u.refreshRanking(), u.refreshPreview())
+			}
+		case "e":
+			switch u.list.SelectedItem().(type) {
+			case aliasItem:
+				u.status = "aliases are edited in the config file, see sttr config"
+				return u, nil
+			case detectItem:
+				u.status = "press r to leave the suggestions and edit the flags"
+				return u, nil
+			}
+			if p, ok := u.highlighted(); ok {
+				u.form = newForm(p, u.flags[p.Name()])
+				return u, nil
+			}
 		}
-	case tea.WindowSizeMsg:
-		h, w := appStyle.GetFrameSize()
-		u.list.SetSize(msg.Width-h, msg.Height-w)
 	}
 
 	var cmd tea.Cmd
-	u.list, cmd = u.list.Update(msg)
-	return u, cmd
+	switch u.focus {
+	case focusList:
+		u.list, cmd = u.list.Update(msg)
+	case focusInput:
+		u.input, cmd = u.input.Update(msg)
+	case focusOutput:
+		u.output, cmd = u.output.Update(msg)
+	}
+	cmds = append(cmds, cmd, u.refreshRanking(), u.refreshPreview())
+
+	return u, tea.Batch(cmds...)
+}
+
+// refreshRanking detects in the background the decoders which succeed on
+// the output of the applied steps when ranking, and the input changed since
+// the last ranking
+func (u *UI) refreshRanking() tea.Cmd {
+	if !u.ranking {
+		return nil
+	}
+	input := u.input.Value()
+	key := fmt.Sprintf("%s\x00%s", u.steps.CommandLine(), input)
+	if key == u.rankedOf {
+		return nil
+	}
+	u.rankedOf = key
+	u.rankingID++
+
+	id, steps := u.rankingID, slices.Clone(u.steps)
+	return func() tea.Msg {
+		if len(steps) > 0 {
+			out, err := steps.Transform([]byte(input))
+			if err != nil {
+				out = ""
+			}
+			input = out
+		}
+		var items []list.Item
+		for _, c := range processors.Detect([]byte(input)) {
+			items = append(items, detectItem{c})
+		}
+		return ranking{id: id, items: items}
+	}
+}
+
+// nextCategory moves the list to the next or previous category having
+// processors, going through every processor between the last and the first
+func (u *UI) nextCategory(forward bool) {
+	categories := []string{""}
+	for _, c := range processors.Categories {
+		if len(processors.ByCategory(c)) > 0 {
+			categories = append(categories, c)
+		}
+	}
+	i := slices.Index(categories, u.category)
+	if forward {
+		i++
+	} else {
+		i += len(categories) - 1
+	}
+	u.setCategory(categories[i%len(categories)])
+}
+
+// setCategory lists the processors of category, or every processor and
+// alias when category is empty
+func (u *UI) setCategory(category string) {
+	u.category = category
+	u.ranking, u.rankedOf = false, ""
+	u.list.ResetFilter()
+	if category == "" {
+		u.list.Title = "Select transformation"
+		u.list.SetItems(append(slices.Clone(u.aliases), listItems(processors.All())...))
+	} else {
+		u.list.Title = "Select transformation: " + processors.CategoryTitle(category)
+		u.list.SetItems(listItems(processors.ByCategory(category)))
+	}
+	u.list.ResetSelected()
+}
+
+// refreshPreview runs the applied steps and the highlighted processor in the
+// background when the input, the chain or the flags changed since the last run
+func (u *UI) refreshPreview() tea.Cmd {
+	chain := u.chain()
+	if len(chain) == 0 {
+		u.previewOf = ""
+		u.preview = preview{}
+		u.setOutput()
+		return nil
+	}
+
+	input := u.input.Value()
+	key := fmt.Sprintf("%s\x00%s", chain.CommandLine(), input)
+	if key == u.previewOf {
+		return nil
+	}
+	u.previewOf = key
+	u.previewID++
+
+	id := u.previewID
+	return func() tea.Msg {
+		out, err := chain.Transform([]byte(input))
+		return preview{id: id, out: out, err: err}
+	}
+}
+
+// chain returns the applied steps followed by the highlighted processor
+func (u *UI) chain() processors.Chain {
+	chain := append(processors.Chain(nil), u.steps...)
+	if c, ok := u.list.SelectedItem().(chainItem); ok {
+		return append(chain, c.Chain()...)
+	}
+	p, ok := u.highlighted()
+	if !ok {
+		return chain
+	}
+
+	flags := u.flags[p.Name()]
+	if u.form != nil && u.form.processor.Name() == p.Name() {
+		// preview the values being edited as long as they are valid
+		if f, err := u.form.Flags(); err == nil {
+			flags = f
+		}
+	}
+	return append(chain, processors.ChainStep{Processor: p, Flags: flags})
+}
+
+// highlighted returns the processor under the cursor in the list
+func (u *UI) highlighted() (processors.Processor, bool) {
+	i, ok := u.list.SelectedItem().(item)
+	if !ok {
+		return nil, false
+	}
+	return i.Processor, true
+}
+
+func (u *UI) setFocus(f focus) {
+	u.focus = f
+	if f == focusInput {
+		u.input.Focus()
+	} else {
+		u.input.Blur()
+	}
+}
+
+// layout sizes the panes, the list takes the left column and the input
+// and output panes share the right one
+func (u *UI) layout() {
+	fw, fh := paneStyle.GetFrameSize()
+	help := 1
+
+	listWidth := u.width * 2 / 5
+	rightWidth := u.width - listWidth
+	inputHeight := (u.height - help) / 3
+	outputHeight := u.height - help - inputHeight
+
+	u.list.SetSize(max(listWidth-fw, 0), max(u.height-help-fh, 0))
+	// one line of each pane is used by its title
+	u.input.SetWidth(max(rightWidth-fw, 0))
+	u.input.SetHeight(max(inputHeight-fh-1, 1))
+	u.output.Width = max(rightWidth-fw, 0)
+	u.output.Height = max(outputHeight-fh-1, 1)
+}
+
+// setOutput renders the last preview into the output pane, errors are shown inline
+func (u *UI) setOutput() {
+	style := lipgloss.NewStyle().Width(u.output.Width)
+	if u.preview.err != nil {
+		u.output.SetContent(style.Foreground(errorStyle).Render("error: " + u.preview.err.Error()))
+		return
+	}
+	u.output.SetContent(style.Render(printable(u.preview.out)))
+}
+
+// printable returns binary output as a hex dump so it can be shown in the terminal
+func printable(out string) string {
+	if processors.IsText([]byte(out)) {
+		return out
+	}
+	return hex.Dump([]byte(out))
+}
+
+// copyOutput copies the previewed output to the clipboard
+func (u *UI) copyOutput() {
+	if u.preview.err != nil {
+		u.status = "nothing to copy, the output is an error"
+		return
+	}
+	if err := utils.WriteClipboard(u.preview.out); err != nil {
+		u.status = "copy failed: " + err.Error()
+		return
+	}
+	u.status = "output copied to the clipboard"
+}
+
+// outputTitle shows the command line equivalent to the chain being previewed
+func (u *UI) outputTitle() string {
+	chain := u.chain()
+	if len(chain) == 0 {
+		return "Output"
+	}
+	return "Output · $ " + chain.CommandLine()
+}
+
+// updateForm handles key presses while the flag editor is open,
+// enter keeps the values and esc discards them
+func (u *UI) updateForm(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		u.quitting = true
+		return tea.Quit
+	case "esc":
+		u.form = nil
+		return u.refreshPreview()
+	case "enter":
+		flags, err := u.form.Flags()
+		if err != nil {
+			u.form.err = err
+			return nil
+		}
+		u.flags[u.form.processor.Name()] = flags
+		u.form = nil
+		return u.refreshPreview()
+	}
+
+	return tea.Batch(u.form.Update(msg), u.refreshPreview())
+}
+
+func (u *UI) helpText() string {
+	if u.status != "" {
+		return " " + u.status
+	}
+	if u.form != nil {
+		return " ↑/↓: move • space/←/→: change • enter: apply • esc: cancel"
+	}
+	switch u.focus {
+	case focusInput:
+		return " tab: output • esc: processors • ctrl+y: copy output • ctrl+c: quit"
+	case focusOutput:
+		return " ↑/↓: scroll • tab: processors • esc: processors • ctrl+y: copy output • ctrl+c: quit"
+	}
+	help := " tab: edit input • /: filter • [/]: category • r: suggest decoders • e: edit flags • a: apply step • c: copy output • enter: print output • q: quit"
+	if len(u.steps) > 0 {
+		help = fmt.Sprintf(" applied steps: %d • backspace: undo • p: print applied steps •", len(u.steps)) + help
+	}
+	return help
+}
+
+// item adapts a processors.Processor to a list.DefaultItem,
+// processors which don't implement Title or Description get sensible defaults
+type item struct {
+	processors.Processor
+}
+
+func (i item) Title() string {
+	return processors.TitleOf(i.Processor)
+}
+
+func (i item) Description() string {
+	return processors.DescriptionOf(i.Processor)
+}
+
+// FilterValue matches the title, the category and the tags of the processor
+func (i item) FilterValue() string {
+	terms := []string{i.Title(), processors.CategoryTitle(processors.CategoryOf(i.Processor))}
+	return strings.Join(append(terms, processors.TagsOf(i.Processor)...), " ")
+}
+
+// listItems converts processors to list items for the processor picker
+func listItems(ps []processors.Processor) []list.Item {
+	items := make([]list.Item, 0, len(ps))
+	for _, p := range ps {
+		items = append(items, item{p})
+	}
+	return items
+}
+
+// chainItem is a list item running a whole chain instead of a processor
+type chainItem interface {
+	list.Item
+	Chain() processors.Chain
+}
+
+// aliasItem is a user alias in the processor list, it runs a whole chain
+type aliasItem struct {
+	name  string
+	spec  string
+	chain processors.Chain
+}
+
+func (a aliasItem) Title() string {
+	return fmt.Sprintf("%s (alias)", a.name)
+}
+
+func (a aliasItem) Description() string {
+	return a.spec
+}
+
+func (a aliasItem) FilterValue() string {
+	return a.name + " " + a.spec
+}
+
+func (a aliasItem) Chain() processors.Chain {
+	return a.chain
+}
+
+// detectItem is a decoder suggested for the input in the ranking
+type detectItem struct {
+	candidate processors.Candidate
+}
+
+func (d detectItem) Title() string {
+	command := d.candidate.Chain.CommandLine()
+	if d.candidate.Part == "payload" {
+		command = "cut -d. -f2 | " + command
+	}
+	return fmt.Sprintf("%s %.0f%% (%s)", d.candidate.Format, d.candidate.Confidence*100, command)
+}
+
+func (d detectItem) Description() string {
+	return strings.Join(strings.Fields(printable(string(d.candidate.Output))), " ")
+}
+
+func (d detectItem) FilterValue() string {
+	return d.candidate.Format
+}
+
+func (d detectItem) Chain() processors.Chain {
+	if d.candidate.Part == "" {
+		return d.candidate.Chain
+	}
+	return append(processors.Chain{{Processor: partStep{d.candidate.Part}}}, d.candidate.Chain...)
+}
+
+// partStep keeps the part of the input a suggested chain runs on, the
+// payload of a JWT. It only exists in the applied steps of the ui, the
+// command line cuts the part instead.
+type partStep struct {
+	part string
+}
+
+func (p partStep) Name() string {
+	return "cut-" + p.part
+}
+
+func (p partStep) Alias() []string {
+	return nil
+}
+
+func (p partStep) Transform(data []byte, _ ...processors.Flag) (string, error) {
+	parts := strings.Split(strings.TrimSpace(string(data)), ".")
+	if p.part != "payload" || len(parts) != 3 {
+		return "", fmt.Errorf("no %s in the input", p.part)
+	}
+	return parts[1], nil
+}
+
+func (p partStep) Flags() []processors.Flag {
+	return nil
 }
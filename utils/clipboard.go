@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// ErrNoClipboard is returned when no system clipboard is available
+var ErrNoClipboard = errors.New("no system clipboard available (install xclip, xsel or wl-clipboard)")
+
+// ReadClipboard returns the content of the system clipboard
+func ReadClipboard() (string, error) {
+	if clipboard.Unsupported {
+		return "", ErrNoClipboard
+	}
+	return clipboard.ReadAll()
+}
+
+// WriteClipboard copies s to the system clipboard, when there is none
+// (e.g. over SSH) the OSC52 escape sequence asks the terminal to copy it
+func WriteClipboard(s string) error {
+	if !clipboard.Unsupported {
+		if err := clipboard.WriteAll(s); err == nil {
+			return nil
+		}
+	}
+	return WriteOSC52(s)
+}
+
+// WriteOSC52 copies s with the OSC52 escape sequence written to the
+// terminal on stderr, so stdout can still be redirected
+func WriteOSC52(s string) error {
+	seq := OSC52(s)
+	_, err := os.Stderr.WriteString(seq)
+	return err
+}
+
+// OSC52 returns the OSC52 escape sequence copying s, wrapped for tmux
+// and screen when sttr runs inside them
+func OSC52(s string) string {
+	seq := osc52.New(s)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+	return seq.String()
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// HasGlobMeta reports whether pattern contains glob special characters
+func HasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[`)
+}
+
+// Glob returns the regular files matching pattern in lexical order.
+// It supports the filepath.Match syntax plus "**" as a whole path
+// element, matching zero or more directories.
+func Glob(pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return regularFiles(matches), nil
+	}
+
+	// walk from the longest leading part of the pattern without meta characters
+	slash := filepath.ToSlash(filepath.Clean(pattern))
+	parts := strings.Split(slash, "/")
+	n := 0
+	for n < len(parts) && !HasGlobMeta(parts[n]) {
+		n++
+	}
+	root := strings.Join(parts[:n], "/")
+	switch {
+	case root == "" && strings.HasPrefix(slash, "/"):
+		root = "/"
+	case root == "":
+		root = "."
+	}
+	root = filepath.FromSlash(root)
+
+	var matches []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				// like filepath.Glob a missing root only means no matches
+				return fs.SkipAll
+			}
+			// unreadable directories are skipped like the shell does
+			return fs.SkipDir
+		}
+		if !d.Type().IsRegular() && !(d.Type()&fs.ModeSymlink != 0 && isRegularFile(path)) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		ok, err := matchParts(parts[n:], strings.Split(filepath.ToSlash(rel), "/"))
+		if err != nil {
+			return err
+		}
+		if ok {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(matches)
+	return matches, nil
+}
+
+// matchParts matches path elements against pattern elements
+func matchParts(pattern, path []string) (bool, error) {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// try every number of skipped directories
+			for i := 0; i <= len(path); i++ {
+				ok, err := matchParts(pattern[1:], path[i:])
+				if ok || err != nil {
+					return ok, err
+				}
+			}
+			return false, nil
+		}
+		if len(path) == 0 {
+			return false, nil
+		}
+		ok, err := filepath.Match(pattern[0], path[0])
+		if !ok || err != nil {
+			return false, err
+		}
+		pattern, path = pattern[1:], path[1:]
+	}
+	return len(path) == 0, nil
+}
+
+// regularFiles filters out directories and other non regular files
+func regularFiles(paths []string) []string {
+	files := paths[:0]
+	for _, path := range paths {
+		if isRegularFile(path) {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// isRegularFile reports whether path is a regular file, following symlinks
+func isRegularFile(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.Mode().IsRegular()
+}
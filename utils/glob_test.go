@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.json", "sub/c.txt", "sub/deep/d.txt", "sub/deep/e.json", "other/f.txt"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "dir.txt"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+		wantErr bool
+	}{
+		{name: "Single level", pattern: "*.txt", want: []string{"a.txt"}},
+		{name: "Directories are skipped", pattern: "*", want: []string{"a.txt", "b.json"}},
+		{name: "Double star", pattern: "**/*.txt", want: []string{"a.txt", "other/f.txt", "sub/c.txt", "sub/deep/d.txt"}},
+		{name: "Double star under a directory", pattern: "sub/**/*.txt", want: []string{"sub/c.txt", "sub/deep/d.txt"}},
+		{name: "Double star in the middle", pattern: "**/deep/*", want: []string{"sub/deep/d.txt", "sub/deep/e.json"}},
+		{name: "Double star at the end", pattern: "sub/**", want: []string{"sub/c.txt", "sub/deep/d.txt", "sub/deep/e.json"}},
+		{name: "Missing root", pattern: "nope/**/*.txt", want: nil},
+		{name: "No match", pattern: "**/*.yaml", want: nil},
+		{name: "Bad pattern", pattern: "**/[", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Glob(filepath.Join(dir, filepath.FromSlash(tt.pattern)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Glob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var rel []string
+			for _, path := range got {
+				r, err := filepath.Rel(dir, path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				rel = append(rel, filepath.ToSlash(r))
+			}
+			if !reflect.DeepEqual(rel, tt.want) {
+				t.Errorf("Glob() = %v, want %v", rel, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchParts(t *testing.T) {
+	tests := []struct {
+		pattern []string
+		path    []string
+		want    bool
+	}{
+		{pattern: []string{"**"}, path: []string{}, want: true},
+		{pattern: []string{"**", "a"}, path: []string{"a"}, want: true},
+		{pattern: []string{"**", "a"}, path: []string{"x", "y", "a"}, want: true},
+		{pattern: []string{"**", "a"}, path: []string{"a", "b"}, want: false},
+		{pattern: []string{"x", "**", "**", "a"}, path: []string{"x", "a"}, want: true},
+		{pattern: []string{"*.go"}, path: []string{"x", "a.go"}, want: false},
+	}
+	for _, tt := range tests {
+		got, err := matchParts(tt.pattern, tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("matchParts(%v, %v) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
@@ -2,34 +2,109 @@ package utils
 
 import (
 	"bufio"
+	"bytes"
+	"io"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/iancoleman/strcase"
+	"golang.org/x/term"
 )
 
-// ReadMultilineInput read multiple lines from stdin,
-// to return the input, two empty line are expected
+// ReadMultilineInput reads the input from stdin, see ReadStdin.
+// On a terminal two empty lines end the input.
 func ReadMultilineInput() string {
-	str := make([]string, 0)
-	scanner := bufio.NewScanner(os.Stdin)
-	empty := 0
+	s, _ := ReadStdin("")
+	return s
+}
+
+// ReadStdin reads the input from stdin. Pipes and files are read to EOF
+// without their final line break, a terminal is read with ReadTerminated.
+func ReadStdin(terminator string) (string, error) {
+	if !IsTerminal(os.Stdin) {
+		b, err := io.ReadAll(TrimFinalNewline(os.Stdin))
+		return string(b), err
+	}
+	return ReadTerminated(os.Stdin, terminator)
+}
+
+// TrimFinalNewline returns r without the "\n" or "\r\n" ending its content,
+// the line break echo and most commands add to their output.
+func TrimFinalNewline(r io.Reader) io.Reader {
+	return &newlineTrimmer{r: r}
+}
+
+// newlineTrimmer holds back the last two bytes read until r ends
+type newlineTrimmer struct {
+	r   io.Reader
+	buf []byte
+	err error
+}
+
+func (t *newlineTrimmer) Read(p []byte) (int, error) {
 	for {
-		scanner.Scan()
-		text := scanner.Text()
-		str = append(str, text)
-		if len(text) != 0 {
-			empty = 0
-		} else {
-			empty++
-			if empty == 2 {
-				break
+		keep := 2
+		if t.err != nil {
+			keep = 0
+		}
+		if len(t.buf) > keep {
+			n := copy(p, t.buf[:len(t.buf)-keep])
+			t.buf = append(t.buf[:0], t.buf[n:]...)
+			return n, nil
+		}
+		if t.err != nil {
+			return 0, t.err
+		}
+
+		n := len(t.buf)
+		t.buf = slices.Grow(t.buf, max(len(p), 512))
+		m, err := t.r.Read(t.buf[n:cap(t.buf)])
+		t.buf = t.buf[:n+m]
+		if err != nil {
+			t.err = err
+			if err == io.EOF && bytes.HasSuffix(t.buf, []byte("\n")) {
+				t.buf = bytes.TrimSuffix(t.buf[:len(t.buf)-1], []byte("\r"))
 			}
 		}
 	}
-	// Use collected inputs
-	return strings.Join(str[:len(str)-2], "\n")
+}
+
+// ReadTerminated reads lines from r until a line equal to terminator, or
+// until two consecutive empty lines when terminator is empty, or EOF.
+// The terminating lines are not part of the input, lines have no length limit.
+func ReadTerminated(r io.Reader, terminator string) (string, error) {
+	br := bufio.NewReader(r)
+	var lines []string
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		if err == io.EOF && line == "" {
+			break
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		if terminator != "" && line == terminator {
+			break
+		}
+		if terminator == "" && line == "" && len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+			break
+		}
+		lines = append(lines, line)
+		if err == io.EOF {
+			break
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// IsTerminal reports whether f is an interactive terminal
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
 }
 
 func ToKebabCase(input []byte) string {
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReadTerminated(t *testing.T) {
+	long := strings.Repeat("x", 100*1024)
+	tests := []struct {
+		name       string
+		input      string
+		terminator string
+		want       string
+	}{
+		{
+			name:       "Terminator",
+			input:      "a\nb\nEND\nc\n",
+			terminator: "END",
+			want:       "a\nb",
+		},
+		{
+			name:       "Terminator keeps empty lines",
+			input:      "a\n\n\nb\nEND\n",
+			terminator: "END",
+			want:       "a\n\n\nb",
+		},
+		{
+			name:       "Terminator with CRLF",
+			input:      "a\r\nEND\r\nb\r\n",
+			terminator: "END",
+			want:       "a",
+		},
+		{
+			name:       "Terminator only as a whole line",
+			input:      "a END\nEND\n",
+			terminator: "END",
+			want:       "a END",
+		},
+		{
+			name:  "Two empty lines",
+			input: "a\n\nb\n\n\nc\n",
+			want:  "a\n\nb",
+		},
+		{
+			name:  "Leading empty line",
+			input: "\na\n\n\n",
+			want:  "\na",
+		},
+		{
+			name:  "EOF",
+			input: "a\nb",
+			want:  "a\nb",
+		},
+		{
+			name:       "EOF without terminator",
+			input:      "a\n\n\nb\n",
+			terminator: "END",
+			want:       "a\n\n\nb",
+		},
+		{
+			name:  "Empty",
+			input: "",
+			want:  "",
+		},
+		{
+			name:  "Lines over 64KB",
+			input: long + "\n" + long + "\n\n\n",
+			want:  long + "\n" + long,
+		},
+		{
+			name:       "Line over 64KB before the terminator",
+			input:      long + "\nEND\n",
+			terminator: "END",
+			want:       long,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadTerminated(strings.NewReader(tt.input), tt.terminator)
+			if err != nil {
+				t.Fatalf("ReadTerminated() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ReadTerminated() = %.40q (%d bytes), want %.40q (%d bytes)", got, len(got), tt.want, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestTrimFinalNewline(t *testing.T) {
+	long := strings.Repeat("x", 100*1024)
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Line break", input: "a\n", want: "a"},
+		{name: "CRLF", input: "a\r\n", want: "a"},
+		{name: "Only the last line break", input: "a\n\n", want: "a\n"},
+		{name: "Inner line breaks", input: "a\nb\r\nc\n", want: "a\nb\r\nc"},
+		{name: "No line break", input: "a", want: "a"},
+		{name: "Carriage return", input: "a\r", want: "a\r"},
+		{name: "Line break only", input: "\n", want: ""},
+		{name: "Empty", input: "", want: ""},
+		{name: "Long input", input: long + "\n", want: long},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, r := range []io.Reader{strings.NewReader(tt.input), iotest.OneByteReader(strings.NewReader(tt.input))} {
+				got, err := io.ReadAll(TrimFinalNewline(r))
+				if err != nil {
+					t.Fatalf("ReadAll() error = %v", err)
+				}
+				if string(got) != tt.want {
+					t.Errorf("TrimFinalNewline() = %.40q (%d bytes), want %.40q (%d bytes)", got, len(got), tt.want, len(tt.want))
+				}
+			}
+		})
+	}
+}